@@ -0,0 +1,228 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"path"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	"github.com/golang/protobuf/protoc-gen-go/generator"
+	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+	"google.golang.org/grpc/codes"
+
+	"github.com/srvc/grpc-errors/errorspb"
+)
+
+type params struct {
+	standalone bool
+}
+
+func parseParams(s string) (params, error) {
+	var p params
+	if s == "" {
+		return p, nil
+	}
+	for _, kv := range strings.Split(s, ",") {
+		i := strings.Index(kv, "=")
+		if i < 0 {
+			return p, fmt.Errorf("invalid parameter %q", kv)
+		}
+		switch k, v := kv[:i], kv[i+1:]; k {
+		case "standalone":
+			p.standalone = v == "true"
+		default:
+			return p, fmt.Errorf("unknown parameter %q", k)
+		}
+	}
+	return p, nil
+}
+
+func generate(req *plugin.CodeGeneratorRequest) *plugin.CodeGeneratorResponse {
+	resp := &plugin.CodeGeneratorResponse{}
+
+	p, err := parseParams(req.GetParameter())
+	if err != nil {
+		resp.Error = proto.String(err.Error())
+		return resp
+	}
+
+	files := make(map[string]*descriptor.FileDescriptorProto, len(req.ProtoFile))
+	for _, f := range req.ProtoFile {
+		files[f.GetName()] = f
+	}
+
+	for _, name := range req.FileToGenerate {
+		f, ok := files[name]
+		if !ok {
+			resp.Error = proto.String(fmt.Sprintf("file %q is not found in a request", name))
+			return resp
+		}
+		content, ok, err := generateFile(f, p)
+		if err != nil {
+			resp.Error = proto.String(fmt.Sprintf("%s: %v", name, err))
+			return resp
+		}
+		if !ok {
+			continue
+		}
+		resp.File = append(resp.File, &plugin.CodeGeneratorResponse_File{
+			Name:    proto.String(strings.TrimSuffix(name, ".proto") + ".errors.go"),
+			Content: proto.String(content),
+		})
+	}
+
+	return resp
+}
+
+type enumValue struct {
+	name          string
+	number        int32
+	grpcCode      codes.Code
+	annotated     bool
+	description   string
+	retryable     bool
+	publicMessage string
+}
+
+type enum struct {
+	typeName    string
+	valuePrefix string
+	values      []*enumValue
+}
+
+func (e *enum) annotated() bool {
+	for _, v := range e.values {
+		if v.annotated {
+			return true
+		}
+	}
+	return false
+}
+
+func generateFile(f *descriptor.FileDescriptorProto, p params) (string, bool, error) {
+	var enums []*enum
+	for _, e := range f.EnumType {
+		enums = append(enums, newEnum(e, nil))
+	}
+	for _, m := range f.MessageType {
+		enums = append(enums, collectNestedEnums(m, nil)...)
+	}
+
+	var annotated []*enum
+	for _, e := range enums {
+		if e.annotated() {
+			annotated = append(annotated, e)
+		}
+	}
+	if len(annotated) == 0 {
+		return "", false, nil
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by protoc-gen-grpc-errors. DO NOT EDIT.\n// source: %s\n\n", f.GetName())
+	fmt.Fprintf(&buf, "package %s\n\n", goPackageName(f))
+	buf.WriteString("import (\n\tgrpcerrors \"github.com/srvc/grpc-errors\"\n\tcodes \"google.golang.org/grpc/codes\"\n)\n")
+
+	for _, e := range annotated {
+		if p.standalone {
+			fmt.Fprintf(&buf, "\n// %s is an application error code.\ntype %s int32\n\nconst (\n", e.typeName, e.typeName)
+			for _, v := range e.values {
+				fmt.Fprintf(&buf, "\t%s_%s %s = %d\n", e.valuePrefix, v.name, e.typeName, v.number)
+			}
+			buf.WriteString(")\n")
+		}
+
+		fmt.Fprintf(&buf, "\n// %sCodeMap maps %s values to gRPC codes.\nvar %sCodeMap = grpcerrors.CodeMap{\n", e.typeName, e.typeName, e.typeName)
+		for _, v := range e.values {
+			if v.annotated {
+				fmt.Fprintf(&buf, "\t%s_%s: codes.%s,\n", e.valuePrefix, v.name, v.grpcCode)
+			}
+		}
+		buf.WriteString("}\n")
+
+		fmt.Fprintf(&buf, "\n// %sDefinitions is a list of error definitions of %s values.\nvar %sDefinitions = []grpcerrors.ErrorDefinition{\n", e.typeName, e.typeName, e.typeName)
+		for _, v := range e.values {
+			if !v.annotated {
+				continue
+			}
+			fmt.Fprintf(&buf, "\t{\n\t\tCode: %s_%s,\n\t\tGrpcCode: codes.%s,\n", e.valuePrefix, v.name, v.grpcCode)
+			if v.description != "" {
+				fmt.Fprintf(&buf, "\t\tDescription: %q,\n", v.description)
+			}
+			if v.retryable {
+				buf.WriteString("\t\tRetryable: true,\n")
+			}
+			if v.publicMessage != "" {
+				fmt.Fprintf(&buf, "\t\tPublicMessage: %q,\n", v.publicMessage)
+			}
+			buf.WriteString("\t},\n")
+		}
+		buf.WriteString("}\n")
+	}
+
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return "", false, err
+	}
+	return string(src), true, nil
+}
+
+func collectNestedEnums(m *descriptor.DescriptorProto, parents []string) []*enum {
+	parents = append(parents[:len(parents):len(parents)], m.GetName())
+	var enums []*enum
+	for _, e := range m.EnumType {
+		enums = append(enums, newEnum(e, parents))
+	}
+	for _, nested := range m.NestedType {
+		enums = append(enums, collectNestedEnums(nested, parents)...)
+	}
+	return enums
+}
+
+// newEnum builds an enum with Go identifiers in the same way as protoc-gen-go.
+func newEnum(e *descriptor.EnumDescriptorProto, parents []string) *enum {
+	typeName := generator.CamelCaseSlice(append(parents[:len(parents):len(parents)], e.GetName()))
+	valuePrefix := typeName
+	if len(parents) > 0 {
+		valuePrefix = generator.CamelCaseSlice(parents)
+	}
+
+	en := &enum{typeName: typeName, valuePrefix: valuePrefix}
+	for _, v := range e.Value {
+		ev := &enumValue{name: v.GetName(), number: v.GetNumber()}
+		if opts := v.Options; opts != nil {
+			if ext, err := proto.GetExtension(opts, errorspb.E_Code); err == nil {
+				ev.annotated = true
+				ev.grpcCode = codes.Code(*ext.(*errorspb.GrpcCode))
+			}
+			if ext, err := proto.GetExtension(opts, errorspb.E_Description); err == nil {
+				ev.description = *ext.(*string)
+			}
+			if ext, err := proto.GetExtension(opts, errorspb.E_Retryable); err == nil {
+				ev.retryable = *ext.(*bool)
+			}
+			if ext, err := proto.GetExtension(opts, errorspb.E_PublicMessage); err == nil {
+				ev.publicMessage = *ext.(*string)
+			}
+		}
+		en.values = append(en.values, ev)
+	}
+	return en
+}
+
+// goPackageName returns a Go package name of a file in the same way as protoc-gen-go.
+func goPackageName(f *descriptor.FileDescriptorProto) string {
+	if pkg := f.GetOptions().GetGoPackage(); pkg != "" {
+		if i := strings.LastIndex(pkg, ";"); i >= 0 {
+			return pkg[i+1:]
+		}
+		return path.Base(pkg)
+	}
+	if pkg := f.GetPackage(); pkg != "" {
+		return strings.Replace(pkg, ".", "_", -1)
+	}
+	return strings.TrimSuffix(path.Base(f.GetName()), ".proto")
+}
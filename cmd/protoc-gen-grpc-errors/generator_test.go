@@ -0,0 +1,140 @@
+package main
+
+import (
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/protoc-gen-go/descriptor"
+	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+
+	"github.com/srvc/grpc-errors/errorspb"
+)
+
+func newEnumValueDescriptor(t *testing.T, name string, number int32, exts map[*proto.ExtensionDesc]interface{}) *descriptor.EnumValueDescriptorProto {
+	v := &descriptor.EnumValueDescriptorProto{Name: proto.String(name), Number: proto.Int32(number)}
+	if len(exts) > 0 {
+		v.Options = &descriptor.EnumValueOptions{}
+		for desc, ext := range exts {
+			if err := proto.SetExtension(v.Options, desc, ext); err != nil {
+				t.Fatalf("Failed to set an extension: %v", err)
+			}
+		}
+	}
+	return v
+}
+
+// newExampleRequest returns a request equivalent to testdata/example.proto.
+func newExampleRequest(t *testing.T, param string) *plugin.CodeGeneratorRequest {
+	grpcCode := func(c errorspb.GrpcCode) *errorspb.GrpcCode { return &c }
+
+	file := &descriptor.FileDescriptorProto{
+		Name:       proto.String("example.proto"),
+		Package:    proto.String("example"),
+		Dependency: []string{"options.proto"},
+		Options: &descriptor.FileOptions{
+			GoPackage: proto.String("github.com/srvc/grpc-errors/cmd/protoc-gen-grpc-errors/testdata;example"),
+		},
+		EnumType: []*descriptor.EnumDescriptorProto{
+			{
+				Name: proto.String("ErrorCode"),
+				Value: []*descriptor.EnumValueDescriptorProto{
+					newEnumValueDescriptor(t, "ERROR_CODE_UNSPECIFIED", 0, nil),
+					newEnumValueDescriptor(t, "USER_NOT_FOUND", 1, map[*proto.ExtensionDesc]interface{}{
+						errorspb.E_Code:        grpcCode(errorspb.GrpcCode_NOT_FOUND),
+						errorspb.E_Description: proto.String("The user does not exist."),
+					}),
+					newEnumValueDescriptor(t, "RATE_LIMITED", 2, map[*proto.ExtensionDesc]interface{}{
+						errorspb.E_Code:          grpcCode(errorspb.GrpcCode_RESOURCE_EXHAUSTED),
+						errorspb.E_Retryable:     proto.Bool(true),
+						errorspb.E_PublicMessage: proto.String("Please retry after {{.retry_after}}."),
+					}),
+				},
+			},
+		},
+		MessageType: []*descriptor.DescriptorProto{
+			{
+				Name: proto.String("User"),
+				EnumType: []*descriptor.EnumDescriptorProto{
+					{
+						Name: proto.String("Error"),
+						Value: []*descriptor.EnumValueDescriptorProto{
+							newEnumValueDescriptor(t, "ERROR_UNSPECIFIED", 0, nil),
+							newEnumValueDescriptor(t, "INVALID_NAME", 1, map[*proto.ExtensionDesc]interface{}{
+								errorspb.E_Code: grpcCode(errorspb.GrpcCode_INVALID_ARGUMENT),
+							}),
+						},
+					},
+				},
+			},
+		},
+		Syntax: proto.String("proto3"),
+	}
+
+	// Round-trip through the wire format as protoc does.
+	data, err := proto.Marshal(&plugin.CodeGeneratorRequest{
+		FileToGenerate: []string{"example.proto"},
+		Parameter:      proto.String(param),
+		ProtoFile:      []*descriptor.FileDescriptorProto{file},
+	})
+	if err != nil {
+		t.Fatalf("Failed to marshal a request: %v", err)
+	}
+	req := &plugin.CodeGeneratorRequest{}
+	if err := proto.Unmarshal(data, req); err != nil {
+		t.Fatalf("Failed to unmarshal a request: %v", err)
+	}
+	return req
+}
+
+func Test_generate(t *testing.T) {
+	resp := generate(newExampleRequest(t, ""))
+
+	if resp.Error != nil {
+		t.Fatalf("generate returned an error: %s", resp.GetError())
+	}
+
+	if got, want := len(resp.File), 1; got != want {
+		t.Fatalf("generate returned %d files, want %d", got, want)
+	}
+
+	if got, want := resp.File[0].GetName(), "example.errors.go"; got != want {
+		t.Errorf("Generated file name is %q, want %q", got, want)
+	}
+
+	golden, err := ioutil.ReadFile("testdata/example.errors.go.golden")
+	if err != nil {
+		t.Fatalf("Failed to read a golden file: %v", err)
+	}
+
+	if got, want := resp.File[0].GetContent(), string(golden); got != want {
+		t.Errorf("Generated code is\n%s\nwant\n%s", got, want)
+	}
+}
+
+func Test_generate_Standalone(t *testing.T) {
+	resp := generate(newExampleRequest(t, "standalone=true"))
+
+	if resp.Error != nil {
+		t.Fatalf("generate returned an error: %s", resp.GetError())
+	}
+
+	content := resp.File[0].GetContent()
+	for _, want := range []string{
+		"type ErrorCode int32",
+		"ErrorCode_RATE_LIMITED           ErrorCode = 2",
+		"type User_Error int32",
+		"User_INVALID_NAME      User_Error = 1",
+	} {
+		if !strings.Contains(content, want) {
+			t.Errorf("Generated code should contain %q:\n%s", want, content)
+		}
+	}
+}
+
+func Test_generate_WithUnknownParameter(t *testing.T) {
+	if resp := generate(newExampleRequest(t, "unknown=true")); resp.Error == nil {
+		t.Error("generate should return an error")
+	}
+}
@@ -0,0 +1,44 @@
+// Command protoc-gen-grpc-errors is a protoc plugin that generates grpcerrors.CodeMap and error definitions
+// from enum values annotated with options in errorspb/options.proto.
+//
+//	enum ErrorCode {
+//	  ERROR_CODE_UNSPECIFIED = 0;
+//	  USER_NOT_FOUND = 1 [(grpcerrors.code) = NOT_FOUND, (grpcerrors.description) = "The user does not exist."];
+//	}
+//
+// It is expected to run with protoc-gen-go, which generates Go constants of enums.
+// Pass "standalone=true" as a parameter to generate the constants by itself.
+//
+// The options use unregistered extension numbers 51200-51203, so they may collide with other custom enum value options.
+package main
+
+import (
+	"io/ioutil"
+	"os"
+
+	"github.com/golang/protobuf/proto"
+	plugin "github.com/golang/protobuf/protoc-gen-go/plugin"
+)
+
+func main() {
+	data, err := ioutil.ReadAll(os.Stdin)
+	if err != nil {
+		fail(err)
+	}
+	req := &plugin.CodeGeneratorRequest{}
+	if err := proto.Unmarshal(data, req); err != nil {
+		fail(err)
+	}
+	out, err := proto.Marshal(generate(req))
+	if err != nil {
+		fail(err)
+	}
+	if _, err := os.Stdout.Write(out); err != nil {
+		fail(err)
+	}
+}
+
+func fail(err error) {
+	os.Stderr.WriteString("protoc-gen-grpc-errors: " + err.Error() + "\n")
+	os.Exit(1)
+}
@@ -0,0 +1,327 @@
+package grpcerrors
+
+import (
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/pelletier/go-toml"
+	"google.golang.org/grpc/codes"
+	"gopkg.in/yaml.v3"
+)
+
+// CodeMapConfig is a CodeMap loaded from a config file.
+//
+// A config file has a default gRPC code and a list of mappings from status codes to gRPC codes.
+// Status codes are loaded as int or string by their types in a file, and gRPC codes are specified with names like "NOT_FOUND".
+// Integer codes are loaded as plain int, so they never match codes of a named type such as `type Code int`.
+// Convert keys of Map to such a type before using it.
+//
+//	default: INTERNAL
+//	mappings:
+//	  - code: 1
+//	    grpc: INVALID_ARGUMENT
+//	  - code: user_not_found
+//	    grpc: NOT_FOUND
+type CodeMapConfig struct {
+	// Map maps status codes to gRPC codes.
+	Map CodeMap
+	// Default is a gRPC code for status codes that are not contained in Map.
+	// It is codes.Unknown when HasDefault is false.
+	Default codes.Code
+	// HasDefault represents whether a default code is specified.
+	HasDefault bool
+}
+
+// Handler returns a new error handler function for mapping status codes to gRPC's one with the config.
+// When a default code is specified, it works as WithCodeMapFallback with Default as a fallback,
+// and otherwise it works as WithCodeMap.
+func (c *CodeMapConfig) Handler(detailFns ...StatusDetailsFunc) interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+} {
+	if c.HasDefault {
+		return WithCodeMapFallback(c.Map, c.Default, detailFns...)
+	}
+	return WithCodeMap(c.Map, detailFns...)
+}
+
+// ConfigError is an error found at a line in a config file.
+type ConfigError struct {
+	File string
+	Line int
+	Msg  string
+}
+
+// Error returns a message prefixed with the file name and the line number.
+func (e *ConfigError) Error() string {
+	return fmt.Sprintf("%s:%d: %s", e.File, e.Line, e.Msg)
+}
+
+// ConfigErrors is a list of errors found in a config file.
+type ConfigErrors []*ConfigError
+
+// Error returns messages of all errors separated by newlines.
+func (e ConfigErrors) Error() string {
+	msgs := make([]string, 0, len(e))
+	for _, err := range e {
+		msgs = append(msgs, err.Error())
+	}
+	return strings.Join(msgs, "\n")
+}
+
+var grpcCodeByName = map[string]codes.Code{
+	"OK":                  codes.OK,
+	"CANCELLED":           codes.Canceled,
+	"UNKNOWN":             codes.Unknown,
+	"INVALID_ARGUMENT":    codes.InvalidArgument,
+	"DEADLINE_EXCEEDED":   codes.DeadlineExceeded,
+	"NOT_FOUND":           codes.NotFound,
+	"ALREADY_EXISTS":      codes.AlreadyExists,
+	"PERMISSION_DENIED":   codes.PermissionDenied,
+	"RESOURCE_EXHAUSTED":  codes.ResourceExhausted,
+	"FAILED_PRECONDITION": codes.FailedPrecondition,
+	"ABORTED":             codes.Aborted,
+	"OUT_OF_RANGE":        codes.OutOfRange,
+	"UNIMPLEMENTED":       codes.Unimplemented,
+	"INTERNAL":            codes.Internal,
+	"UNAVAILABLE":         codes.Unavailable,
+	"DATA_LOSS":           codes.DataLoss,
+	"UNAUTHENTICATED":     codes.Unauthenticated,
+}
+
+// ParseGrpcCode returns a gRPC code from its canonical name like "NOT_FOUND".
+func ParseGrpcCode(name string) (codes.Code, bool) {
+	c, ok := grpcCodeByName[name]
+	return c, ok
+}
+
+// LoadCodeMapFile reads a CodeMapConfig from a YAML, JSON or TOML file chosen by its extension.
+// All problems in a file are returned as ConfigErrors.
+func LoadCodeMapFile(path string) (*CodeMapConfig, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseCodeMapConfig(path, filepath.Ext(path), data)
+}
+
+// ParseCodeMapConfig parses a CodeMapConfig in format, which is one of ".yaml", ".yml", ".json" or ".toml".
+// name is used for error messages.
+func ParseCodeMapConfig(name, format string, data []byte) (*CodeMapConfig, error) {
+	var (
+		raw *rawCodeMapConfig
+		err error
+	)
+	switch format {
+	case ".yaml", ".yml", ".json":
+		// JSON is parsed as YAML for retrieving line numbers
+		raw, err = parseYAMLCodeMapConfig(name, data)
+	case ".toml":
+		raw, err = parseTOMLCodeMapConfig(name, data)
+	default:
+		return nil, fmt.Errorf("grpcerrors: unsupported code map format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return raw.build(name)
+}
+
+type rawValue struct {
+	value interface{}
+	line  int
+}
+
+type rawMapping struct {
+	code rawValue
+	grpc rawValue
+	line int
+}
+
+type rawCodeMapConfig struct {
+	def      *rawValue
+	mappings []rawMapping
+	errs     ConfigErrors
+}
+
+func (c *rawCodeMapConfig) errorf(name string, line int, format string, args ...interface{}) {
+	c.errs = append(c.errs, &ConfigError{File: name, Line: line, Msg: fmt.Sprintf(format, args...)})
+}
+
+func (c *rawCodeMapConfig) build(name string) (*CodeMapConfig, error) {
+	cfg := &CodeMapConfig{Map: CodeMap{}, Default: codes.Unknown}
+
+	if c.def != nil {
+		if code, ok := c.parseGrpcCode(name, *c.def); ok {
+			cfg.Default, cfg.HasDefault = code, true
+		}
+	}
+
+	lines := map[interface{}]int{}
+	for _, m := range c.mappings {
+		if m.code.value == nil {
+			c.errorf(name, m.line, "mapping has no code")
+			continue
+		}
+		if m.grpc.value == nil {
+			c.errorf(name, m.line, "mapping has no grpc code")
+			continue
+		}
+		grpcCode, ok := c.parseGrpcCode(name, m.grpc)
+		if !ok {
+			continue
+		}
+		if line, ok := lines[m.code.value]; ok {
+			c.errorf(name, m.code.line, "code %v is already mapped at line %d", m.code.value, line)
+			continue
+		}
+		lines[m.code.value] = m.code.line
+		cfg.Map[m.code.value] = grpcCode
+	}
+
+	if len(c.errs) > 0 {
+		sort.SliceStable(c.errs, func(i, j int) bool { return c.errs[i].Line < c.errs[j].Line })
+		return nil, c.errs
+	}
+	return cfg, nil
+}
+
+func (c *rawCodeMapConfig) parseGrpcCode(name string, v rawValue) (codes.Code, bool) {
+	s, ok := v.value.(string)
+	if !ok {
+		c.errorf(name, v.line, "grpc code should be a string, got %v", v.value)
+		return 0, false
+	}
+	code, ok := ParseGrpcCode(s)
+	if !ok {
+		c.errorf(name, v.line, "unknown grpc code name %q", s)
+	}
+	return code, ok
+}
+
+func parseYAMLCodeMapConfig(name string, data []byte) (*rawCodeMapConfig, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("grpcerrors: failed to parse %s: %v", name, err)
+	}
+	raw := &rawCodeMapConfig{}
+	if len(doc.Content) == 0 {
+		return raw, nil
+	}
+	root := doc.Content[0]
+	if root.Kind != yaml.MappingNode {
+		raw.errorf(name, root.Line, "config should be a mapping")
+		return raw, nil
+	}
+	for i := 0; i+1 < len(root.Content); i += 2 {
+		key, val := root.Content[i], root.Content[i+1]
+		switch key.Value {
+		case "default":
+			v := yamlValue(raw, name, val)
+			raw.def = &v
+		case "mappings":
+			if val.Kind != yaml.SequenceNode {
+				raw.errorf(name, val.Line, "mappings should be a list")
+				continue
+			}
+			for _, item := range val.Content {
+				if item.Kind != yaml.MappingNode {
+					raw.errorf(name, item.Line, "mapping should be a mapping")
+					continue
+				}
+				m := rawMapping{line: item.Line}
+				for j := 0; j+1 < len(item.Content); j += 2 {
+					switch k, v := item.Content[j], item.Content[j+1]; k.Value {
+					case "code":
+						m.code = yamlValue(raw, name, v)
+					case "grpc":
+						m.grpc = yamlValue(raw, name, v)
+					default:
+						raw.errorf(name, k.Line, "unknown key %q", k.Value)
+					}
+				}
+				raw.mappings = append(raw.mappings, m)
+			}
+		default:
+			raw.errorf(name, key.Line, "unknown key %q", key.Value)
+		}
+	}
+	return raw, nil
+}
+
+func yamlValue(raw *rawCodeMapConfig, name string, n *yaml.Node) rawValue {
+	v := rawValue{line: n.Line}
+	if n.Kind != yaml.ScalarNode {
+		raw.errorf(name, n.Line, "value should be a string or an integer")
+		return v
+	}
+	switch n.ShortTag() {
+	case "!!int":
+		i, err := strconv.Atoi(n.Value)
+		if err != nil {
+			raw.errorf(name, n.Line, "invalid integer %q", n.Value)
+			return v
+		}
+		v.value = i
+	case "!!str":
+		v.value = n.Value
+	default:
+		raw.errorf(name, n.Line, "value should be a string or an integer, got %q", n.Value)
+	}
+	return v
+}
+
+func parseTOMLCodeMapConfig(name string, data []byte) (*rawCodeMapConfig, error) {
+	tree, err := toml.LoadBytes(data)
+	if err != nil {
+		return nil, fmt.Errorf("grpcerrors: failed to parse %s: %v", name, err)
+	}
+	raw := &rawCodeMapConfig{}
+	for _, key := range tree.Keys() {
+		switch key {
+		case "default":
+			v := tomlValue(raw, name, tree, key)
+			raw.def = &v
+		case "mappings":
+			items, ok := tree.Get(key).([]*toml.Tree)
+			if !ok {
+				raw.errorf(name, tree.GetPosition(key).Line, "mappings should be an array of tables")
+				continue
+			}
+			for _, item := range items {
+				m := rawMapping{line: item.Position().Line}
+				for _, k := range item.Keys() {
+					switch k {
+					case "code":
+						m.code = tomlValue(raw, name, item, k)
+					case "grpc":
+						m.grpc = tomlValue(raw, name, item, k)
+					default:
+						raw.errorf(name, item.GetPosition(k).Line, "unknown key %q", k)
+					}
+				}
+				raw.mappings = append(raw.mappings, m)
+			}
+		default:
+			raw.errorf(name, tree.GetPosition(key).Line, "unknown key %q", key)
+		}
+	}
+	return raw, nil
+}
+
+func tomlValue(raw *rawCodeMapConfig, name string, tree *toml.Tree, key string) rawValue {
+	v := rawValue{line: tree.GetPosition(key).Line}
+	switch val := tree.Get(key).(type) {
+	case int64:
+		v.value = int(val)
+	case string:
+		v.value = val
+	default:
+		raw.errorf(name, v.line, "value should be a string or an integer, got %v", val)
+	}
+	return v
+}
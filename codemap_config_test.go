@@ -0,0 +1,99 @@
+package grpcerrors
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_LoadCodeMapFile(t *testing.T) {
+	want := &CodeMapConfig{
+		Map: CodeMap{
+			50:               codes.PermissionDenied,
+			"user_not_found": codes.NotFound,
+		},
+		Default:    codes.Internal,
+		HasDefault: true,
+	}
+
+	for _, path := range []string{"testdata/codemap.yaml", "testdata/codemap.json", "testdata/codemap.toml"} {
+		t.Run(path, func(t *testing.T) {
+			got, err := LoadCodeMapFile(path)
+			if err != nil {
+				t.Fatalf("Failed to load a code map: %v", err)
+			}
+
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("Loaded code map is %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_CodeMapConfig_Handler(t *testing.T) {
+	cases := []struct {
+		test string
+		cfg  *CodeMapConfig
+		code interface{}
+		want codes.Code
+	}{
+		{
+			test: "mapped code",
+			cfg:  &CodeMapConfig{Map: CodeMap{50: codes.PermissionDenied}, Default: codes.Internal, HasDefault: true},
+			code: 50,
+			want: codes.PermissionDenied,
+		},
+		{
+			test: "unmapped code with default",
+			cfg:  &CodeMapConfig{Map: CodeMap{50: codes.PermissionDenied}, Default: codes.Internal, HasDefault: true},
+			code: "config_unmapped",
+			want: codes.Internal,
+		},
+		{
+			test: "unmapped code without default",
+			cfg:  &CodeMapConfig{Map: CodeMap{50: codes.PermissionDenied}},
+			code: "config_unmapped",
+			want: codes.Unknown,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			err := c.cfg.Handler().HandleUnaryServerError(
+				context.Background(),
+				nil,
+				&grpc.UnaryServerInfo{FullMethod: "/errorstesting.TestService/EmptyCall"},
+				fail.Wrap(errors.New("error"), fail.WithCode(c.code)),
+			)
+
+			if got, want := status.Code(err), c.want; got != want {
+				t.Errorf("The returned error has error code %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_LoadCodeMapFile_WhenAFileIsInvalid(t *testing.T) {
+	_, err := LoadCodeMapFile("testdata/codemap_invalid.yaml")
+
+	errs, ok := err.(ConfigErrors)
+	if !ok {
+		t.Fatalf("The returned error should be ConfigErrors: %v", err)
+	}
+
+	want := ConfigErrors{
+		{File: "testdata/codemap_invalid.yaml", Line: 1, Msg: `unknown grpc code name "INTERNAL_ERROR"`},
+		{File: "testdata/codemap_invalid.yaml", Line: 5, Msg: "code 50 is already mapped at line 3"},
+		{File: "testdata/codemap_invalid.yaml", Line: 8, Msg: `unknown grpc code name "NOT_FOUN"`},
+	}
+
+	if !reflect.DeepEqual(errs, want) {
+		t.Errorf("The returned errors are\n%v\nwant\n%v", errs, want)
+	}
+}
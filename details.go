@@ -0,0 +1,156 @@
+package grpcerrors
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/grpclog"
+	"google.golang.org/grpc/status"
+
+	"github.com/srvc/grpc-errors/errorspb"
+)
+
+// StatusDetailsFunc returns messages that are attached to a gRPC status built from an application error.
+// When any of the messages cannot be marshaled, the status is sent without details and the failure is logged with grpclog.
+type StatusDetailsFunc func(context.Context, *fail.Error) []proto.Message
+
+// logDetailsError logs a failure on attaching details to a status. It is replaced in tests.
+var logDetailsError = func(code codes.Code, err error) {
+	grpclog.Errorf("grpcerrors: failed to attach details to a status with code %v, and it is sent without details: %v", code, err)
+}
+
+// CorrelationIDFunc returns an identifier to correlate an error with server-side logs and reports.
+type CorrelationIDFunc func(context.Context) string
+
+// FailDetails returns a new StatusDetailsFunc that encodes fail.Error as errorspb.FailDetail.
+// A correlation ID is not set when idFn is nil.
+// The original error is not encoded, and params used by this package, prefixed with "grpcerrors.", are omitted.
+func FailDetails(idFn CorrelationIDFunc) StatusDetailsFunc {
+	return func(c context.Context, err *fail.Error) []proto.Message {
+		detail := &errorspb.FailDetail{
+			Messages:  err.Messages,
+			Ignorable: err.Ignorable,
+			Tags:      err.Tags,
+		}
+		if err.Code != nil {
+			detail.Code = fmt.Sprint(err.Code)
+		}
+		for k, v := range err.Params {
+			if strings.HasPrefix(k, internalParamPrefix) {
+				continue
+			}
+			if detail.Params == nil {
+				detail.Params = make(map[string]string, len(err.Params))
+			}
+			detail.Params[k] = fmt.Sprint(v)
+		}
+		if idFn != nil {
+			detail.CorrelationId = idFn(c)
+		}
+		return []proto.Message{detail}
+	}
+}
+
+// WithFailDetailRestorer returns a new error handler function for clients that restores fail.Error from errorspb.FailDetail.
+// The restored error wraps an error of the gRPC status, and its Error method returns the status message.
+// A code is restored to a key of the given CodeMap that has the same string representation,
+// or it is left as a string when no keys are matched.
+// It returns an original error when a gRPC status does not have errorspb.FailDetail.
+func WithFailDetailRestorer(m CodeMap) interface {
+	UnaryClientErrorHandler
+	StreamClientErrorHandler
+} {
+	keys := make([]interface{}, 0, len(m))
+	for code := range m {
+		keys = append(keys, code)
+	}
+	return WithStatusHandler(func(c context.Context, st *status.Status) error {
+		if detail := failDetailFromStatus(st); detail != nil {
+			err, _ := restoreFail(st, detail, keys)
+			return fail.Wrap(err)
+		}
+		return st.Err()
+	})
+}
+
+func failDetailFromStatus(st *status.Status) *errorspb.FailDetail {
+	for _, d := range st.Details() {
+		if detail, ok := d.(*errorspb.FailDetail); ok {
+			return detail
+		}
+	}
+	return nil
+}
+
+// restoreFail returns fail.Error restored from detail, and whether a code is restored to one of codes.
+// A code is restored to one of codes that has the same string representation, or it is left as a string.
+func restoreFail(st *status.Status, detail *errorspb.FailDetail, codes []interface{}) (*fail.Error, bool) {
+	matched := false
+	err := &fail.Error{
+		Err:       newRestoredStatusError(st, detail.Messages),
+		Messages:  detail.Messages,
+		Ignorable: detail.Ignorable,
+		Tags:      detail.Tags,
+	}
+	if detail.Code != "" {
+		err.Code = detail.Code
+		for _, code := range codes {
+			if fmt.Sprint(code) == detail.Code {
+				err.Code = code
+				matched = true
+				break
+			}
+		}
+	}
+	if len(detail.Params) > 0 {
+		err.Params = make(fail.H, len(detail.Params))
+		for k, v := range detail.Params {
+			err.Params[k] = v
+		}
+	}
+	return err, matched
+}
+
+// restoredStatusError is an error of a gRPC status restored on clients.
+// Its message is a status message without restored messages, so that fail.Error does not repeat them.
+type restoredStatusError struct {
+	st  *status.Status
+	msg string
+}
+
+func newRestoredStatusError(st *status.Status, messages []string) error {
+	msg := st.Message()
+	if len(messages) > 0 {
+		msg = strings.TrimPrefix(msg, strings.Join(messages, ": ")+": ")
+	}
+	return &restoredStatusError{st: st, msg: msg}
+}
+
+func (e *restoredStatusError) Error() string {
+	return e.msg
+}
+
+// GRPCStatus returns the gRPC status. It makes status.FromError and status.Code work with the error.
+func (e *restoredStatusError) GRPCStatus() *status.Status {
+	return e.st
+}
+
+func newStatusError(c context.Context, code codes.Code, msg string, err *fail.Error, detailFns []StatusDetailsFunc) error {
+	st := status.New(code, msg)
+	var details []proto.Message
+	for _, f := range detailFns {
+		details = append(details, f(c, err)...)
+	}
+	if len(details) > 0 {
+		if detailed, dErr := st.WithDetails(details...); dErr == nil {
+			st = detailed
+		} else {
+			logDetailsError(code, dErr)
+		}
+	}
+	return st.Err()
+}
@@ -0,0 +1,78 @@
+package grpcerrors
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/srvc/grpc-errors/errorspb"
+)
+
+func Test_FailDetails(t *testing.T) {
+	err := fail.Unwrap(fail.Wrap(
+		errors.New("pq: relation users does not exist"),
+		fail.WithCode(50),
+		fail.WithMessage("failed to find a user"),
+		fail.WithParam("id", 1),
+		WithFieldViolation("name", "must not be empty"),
+		WithPublicMessage("user not found"),
+	))
+
+	msgs := FailDetails(nil)(context.Background(), err)
+	if got, want := len(msgs), 1; got != want {
+		t.Fatalf("FailDetails returned %d messages, want %d", got, want)
+	}
+
+	detail, ok := msgs[0].(*errorspb.FailDetail)
+	if !ok {
+		t.Fatalf("FailDetails returned %T, want *errorspb.FailDetail", msgs[0])
+	}
+
+	want := &errorspb.FailDetail{
+		Code:     "50",
+		Messages: []string{"failed to find a user"},
+		Params:   map[string]string{"id": "1"},
+	}
+	if !proto.Equal(detail, want) {
+		t.Errorf("FailDetails returned %v, want %v", detail, want)
+	}
+
+	if got := proto.MarshalTextString(detail); strings.Contains(got, "pq:") {
+		t.Errorf("FailDetail should not contain the original error: %s", got)
+	}
+}
+
+type unmarshalableMessage struct{}
+
+func (*unmarshalableMessage) Reset()                   {}
+func (*unmarshalableMessage) String() string           { return "unmarshalable" }
+func (*unmarshalableMessage) ProtoMessage()            {}
+func (*unmarshalableMessage) Marshal() ([]byte, error) { return nil, errors.New("cannot be marshaled") }
+
+func Test_newStatusError_WhenDetailsCannotBeAttached(t *testing.T) {
+	var logged []error
+	defer func(f func(codes.Code, error)) { logDetailsError = f }(logDetailsError)
+	logDetailsError = func(_ codes.Code, err error) { logged = append(logged, err) }
+
+	detailFn := func(context.Context, *fail.Error) []proto.Message {
+		return []proto.Message{&unmarshalableMessage{}}
+	}
+	err := newStatusError(context.Background(), codes.InvalidArgument, "invalid", fail.Unwrap(fail.New("invalid")), []StatusDetailsFunc{detailFn})
+
+	st, _ := status.FromError(err)
+	if got, want := st.Code(), codes.InvalidArgument; got != want {
+		t.Errorf("The returned status has code %v, want %v", got, want)
+	}
+	if got := st.Details(); len(got) != 0 {
+		t.Errorf("The returned status has details %v, want none", got)
+	}
+	if got, want := len(logged), 1; got != want {
+		t.Errorf("The failure was logged %d times, want %d", got, want)
+	}
+}
@@ -0,0 +1,111 @@
+package grpcerrors
+
+import (
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+)
+
+// internalParamPrefix is a prefix of fail.Error params used by this package.
+const internalParamPrefix = "grpcerrors."
+
+// Keys of fail.Error params that are read by standard error detail builders.
+const (
+	FieldViolationsParam        = "grpcerrors.field_violations"
+	RetryDelayParam             = "grpcerrors.retry_delay"
+	QuotaViolationsParam        = "grpcerrors.quota_violations"
+	PreconditionViolationsParam = "grpcerrors.precondition_violations"
+)
+
+// WithFieldViolation annotates an error with a field violation that is converted into errdetails.BadRequest.
+func WithFieldViolation(field, description string) fail.Annotator {
+	return func(err *fail.Error) {
+		vs, _ := err.Params[FieldViolationsParam].([]*errdetails.BadRequest_FieldViolation)
+		vs = append(vs[:len(vs):len(vs)], &errdetails.BadRequest_FieldViolation{Field: field, Description: description})
+		fail.WithParam(FieldViolationsParam, vs)(err)
+	}
+}
+
+// WithRetryDelay annotates an error with a duration that is converted into errdetails.RetryInfo.
+func WithRetryDelay(d time.Duration) fail.Annotator {
+	return fail.WithParam(RetryDelayParam, d)
+}
+
+// WithQuotaViolation annotates an error with a quota violation that is converted into errdetails.QuotaFailure.
+func WithQuotaViolation(subject, description string) fail.Annotator {
+	return func(err *fail.Error) {
+		vs, _ := err.Params[QuotaViolationsParam].([]*errdetails.QuotaFailure_Violation)
+		vs = append(vs[:len(vs):len(vs)], &errdetails.QuotaFailure_Violation{Subject: subject, Description: description})
+		fail.WithParam(QuotaViolationsParam, vs)(err)
+	}
+}
+
+// WithPreconditionViolation annotates an error with a precondition violation that is converted into errdetails.PreconditionFailure.
+func WithPreconditionViolation(typ, subject, description string) fail.Annotator {
+	return func(err *fail.Error) {
+		vs, _ := err.Params[PreconditionViolationsParam].([]*errdetails.PreconditionFailure_Violation)
+		vs = append(vs[:len(vs):len(vs)], &errdetails.PreconditionFailure_Violation{Type: typ, Subject: subject, Description: description})
+		fail.WithParam(PreconditionViolationsParam, vs)(err)
+	}
+}
+
+// BadRequestDetails returns a new StatusDetailsFunc that builds errdetails.BadRequest from field violations.
+func BadRequestDetails() StatusDetailsFunc {
+	return func(c context.Context, err *fail.Error) []proto.Message {
+		if vs, ok := err.Params[FieldViolationsParam].([]*errdetails.BadRequest_FieldViolation); ok && len(vs) > 0 {
+			return []proto.Message{&errdetails.BadRequest{FieldViolations: vs}}
+		}
+		return nil
+	}
+}
+
+// RetryInfoDetails returns a new StatusDetailsFunc that builds errdetails.RetryInfo from a retry delay.
+func RetryInfoDetails() StatusDetailsFunc {
+	return func(c context.Context, err *fail.Error) []proto.Message {
+		if d, ok := err.Params[RetryDelayParam].(time.Duration); ok {
+			return []proto.Message{&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(d)}}
+		}
+		return nil
+	}
+}
+
+// QuotaFailureDetails returns a new StatusDetailsFunc that builds errdetails.QuotaFailure from quota violations.
+func QuotaFailureDetails() StatusDetailsFunc {
+	return func(c context.Context, err *fail.Error) []proto.Message {
+		if vs, ok := err.Params[QuotaViolationsParam].([]*errdetails.QuotaFailure_Violation); ok && len(vs) > 0 {
+			return []proto.Message{&errdetails.QuotaFailure{Violations: vs}}
+		}
+		return nil
+	}
+}
+
+// PreconditionFailureDetails returns a new StatusDetailsFunc that builds errdetails.PreconditionFailure from precondition violations.
+func PreconditionFailureDetails() StatusDetailsFunc {
+	return func(c context.Context, err *fail.Error) []proto.Message {
+		if vs, ok := err.Params[PreconditionViolationsParam].([]*errdetails.PreconditionFailure_Violation); ok && len(vs) > 0 {
+			return []proto.Message{&errdetails.PreconditionFailure{Violations: vs}}
+		}
+		return nil
+	}
+}
+
+// StandardDetails returns a new StatusDetailsFunc that builds all standard error details supported by this package.
+func StandardDetails() StatusDetailsFunc {
+	fns := []StatusDetailsFunc{
+		BadRequestDetails(),
+		RetryInfoDetails(),
+		QuotaFailureDetails(),
+		PreconditionFailureDetails(),
+	}
+	return func(c context.Context, err *fail.Error) []proto.Message {
+		var details []proto.Message
+		for _, f := range fns {
+			details = append(details, f(c, err)...)
+		}
+		return details
+	}
+}
@@ -0,0 +1,126 @@
+package grpcerrors
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/golang/protobuf/ptypes"
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+
+	"github.com/srvc/grpc-errors/testing"
+	"github.com/srvc/grpc-errors/testing/assert"
+)
+
+type badRequestService struct {
+	errorstesting.UnimplementedTestServiceServer
+}
+
+func (s *badRequestService) EmptyCall(context.Context, *errorstesting.Empty) (*errorstesting.Empty, error) {
+	return nil, fail.Wrap(
+		errors.New("This request is invalid"),
+		fail.WithCode(50),
+		WithFieldViolation("name", "must not be empty"),
+		WithFieldViolation("age", "must be positive"),
+		WithRetryDelay(3*time.Second),
+	)
+}
+
+func Test_UnaryServerInterceptor_WithStandardDetails(t *testing.T) {
+	ctx := errorstesting.CreateTestContext(t)
+	ctx.Service = &badRequestService{}
+	ctx.AddUnaryServerInterceptor(
+		UnaryServerInterceptor(
+			WithCodeMap(CodeMap{50: codes.InvalidArgument}, StandardDetails()),
+		),
+	)
+	ctx.Setup()
+	defer ctx.Teardown()
+
+	_, err := ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+
+	assert.Code(t, err, codes.InvalidArgument)
+
+	var badRequest errdetails.BadRequest
+	if assert.HasDetail(t, err, &badRequest) {
+		if got, want := len(badRequest.FieldViolations), 2; got != want {
+			t.Errorf("The returned BadRequest has %d field violations, want %d", got, want)
+		} else if got, want := badRequest.FieldViolations[1].Field, "age"; got != want {
+			t.Errorf("The returned BadRequest has field %q, want %q", got, want)
+		}
+	}
+
+	var retryInfo errdetails.RetryInfo
+	if assert.HasDetail(t, err, &retryInfo) {
+		if d, err := ptypes.Duration(retryInfo.RetryDelay); err != nil || d != 3*time.Second {
+			t.Errorf("The returned RetryInfo has delay %v, want %v", d, 3*time.Second)
+		}
+	}
+}
+
+func Test_StatusDetailsFuncs(t *testing.T) {
+	err := fail.Unwrap(fail.Wrap(
+		errors.New("This request is invalid"),
+		WithFieldViolation("name", "must not be empty"),
+		WithFieldViolation("age", "must be positive"),
+		WithRetryDelay(3*time.Second),
+		WithQuotaViolation("user:1", "daily limit exceeded"),
+		WithQuotaViolation("project:1", "monthly limit exceeded"),
+		WithPreconditionViolation("TOS", "user:1", "terms of service not accepted"),
+		WithPreconditionViolation("STATE", "order:1", "order already shipped"),
+	))
+
+	badRequest := &errdetails.BadRequest{FieldViolations: []*errdetails.BadRequest_FieldViolation{
+		{Field: "name", Description: "must not be empty"},
+		{Field: "age", Description: "must be positive"},
+	}}
+	retryInfo := &errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(3 * time.Second)}
+	quotaFailure := &errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{
+		{Subject: "user:1", Description: "daily limit exceeded"},
+		{Subject: "project:1", Description: "monthly limit exceeded"},
+	}}
+	preconditionFailure := &errdetails.PreconditionFailure{Violations: []*errdetails.PreconditionFailure_Violation{
+		{Type: "TOS", Subject: "user:1", Description: "terms of service not accepted"},
+		{Type: "STATE", Subject: "order:1", Description: "order already shipped"},
+	}}
+
+	cases := []struct {
+		test    string
+		fn      StatusDetailsFunc
+		details []proto.Message
+	}{
+		{test: "BadRequestDetails", fn: BadRequestDetails(), details: []proto.Message{badRequest}},
+		{test: "RetryInfoDetails", fn: RetryInfoDetails(), details: []proto.Message{retryInfo}},
+		{test: "QuotaFailureDetails", fn: QuotaFailureDetails(), details: []proto.Message{quotaFailure}},
+		{test: "PreconditionFailureDetails", fn: PreconditionFailureDetails(), details: []proto.Message{preconditionFailure}},
+		{
+			test:    "StandardDetails",
+			fn:      StandardDetails(),
+			details: []proto.Message{badRequest, retryInfo, quotaFailure, preconditionFailure},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			details := c.fn(context.Background(), err)
+
+			if got, want := len(details), len(c.details); got != want {
+				t.Fatalf("Built %d details, want %d", got, want)
+			}
+
+			for i, want := range c.details {
+				if got := details[i]; !proto.Equal(got, want) {
+					t.Errorf("Built detail %v, want %v", got, want)
+				}
+			}
+
+			if got := c.fn(context.Background(), fail.Unwrap(fail.New("no details"))); len(got) != 0 {
+				t.Errorf("Built %v from an error without params, want nothing", got)
+			}
+		})
+	}
+}
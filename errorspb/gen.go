@@ -0,0 +1,4 @@
+// Package errorspb provides protocol buffer messages that carry application errors on gRPC statuses.
+package errorspb
+
+//go:generate protoc -I ./ ./details.proto ./options.proto --go_out=Mgoogle/protobuf/descriptor.proto=github.com/golang/protobuf/protoc-gen-go/descriptor:.
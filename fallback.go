@@ -0,0 +1,72 @@
+package grpcerrors
+
+import (
+	"sync"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+var unmappedCodes = struct {
+	sync.Mutex
+	counts map[interface{}]int
+}{counts: make(map[interface{}]int)}
+
+func recordUnmappedCode(code interface{}) {
+	unmappedCodes.Lock()
+	defer unmappedCodes.Unlock()
+	unmappedCodes.counts[code]++
+}
+
+func resetUnmappedCodes() {
+	unmappedCodes.Lock()
+	defer unmappedCodes.Unlock()
+	unmappedCodes.counts = make(map[interface{}]int)
+}
+
+// UnmappedCodes returns status codes that have been handled with fallback codes in the process, and their counts.
+func UnmappedCodes() map[interface{}]int {
+	unmappedCodes.Lock()
+	defer unmappedCodes.Unlock()
+	counts := make(map[interface{}]int, len(unmappedCodes.counts))
+	for code, n := range unmappedCodes.counts {
+		counts[code] = n
+	}
+	return counts
+}
+
+// WithCodeMapFallback returns a new error handler function for mapping status codes to gRPC's one.
+// Unlike WithCodeMap, status codes that are not contained in m are mapped to fallback and recorded to UnmappedCodes.
+func WithCodeMapFallback(m CodeMap, fallback codes.Code, detailFns ...StatusDetailsFunc) interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+} {
+	return WithFailHandler(func(c context.Context, err *fail.Error) error {
+		if code, ok := m[err.Code]; ok {
+			return newStatusError(c, code, err.Error(), err, detailFns)
+		}
+		recordUnmappedCode(err.Code)
+		return newStatusError(c, fallback, err.Error(), err, detailFns)
+	})
+}
+
+// WithStrictCodeMap returns a new error handler function for mapping status codes to gRPC's one.
+// Status codes that are not contained in m are regarded as bugs.
+// Errors with them are passed to report without the ignorability, and they are mapped to fallback and recorded to UnmappedCodes.
+func WithStrictCodeMap(m CodeMap, fallback codes.Code, report FailHandlerFunc, detailFns ...StatusDetailsFunc) interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+} {
+	return WithFailHandler(func(c context.Context, err *fail.Error) error {
+		if code, ok := m[err.Code]; ok {
+			return newStatusError(c, code, err.Error(), err, detailFns)
+		}
+		recordUnmappedCode(err.Code)
+		err.Ignorable = false
+		if rErr := fail.Unwrap(report(c, err)); rErr != nil {
+			err = rErr
+		}
+		return newStatusError(c, fallback, err.Error(), err, detailFns)
+	})
+}
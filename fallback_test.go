@@ -0,0 +1,95 @@
+package grpcerrors
+
+import (
+	"testing"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/srvc/grpc-errors/testing"
+)
+
+func Test_UnaryServerInterceptor_WithCodeMapFallback(t *testing.T) {
+	cases := []struct {
+		test     string
+		server   errorstesting.TestServiceServer
+		strict   bool
+		code     codes.Code
+		reported bool
+	}{
+		{
+			test:   "lenient mode with code that contained CodeMap",
+			server: &errorWithStatusService{Code: 50},
+			code:   codes.PermissionDenied,
+		},
+		{
+			test:   "lenient mode with unknown code",
+			server: &errorWithStatusService{Code: 1051},
+			code:   codes.Internal,
+		},
+		{
+			test:   "strict mode with code that contained CodeMap",
+			server: &errorWithStatusService{Code: 50},
+			strict: true,
+			code:   codes.PermissionDenied,
+		},
+		{
+			test:     "strict mode with unknown code",
+			server:   &errorWithAnnotationsService{Code: 1052},
+			strict:   true,
+			code:     codes.Internal,
+			reported: true,
+		},
+	}
+
+	resetUnmappedCodes()
+	defer resetUnmappedCodes()
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			var reported bool
+
+			m := CodeMap{50: codes.PermissionDenied}
+			h := WithCodeMapFallback(m, codes.Internal)
+			if c.strict {
+				h = WithStrictCodeMap(m, codes.Internal, func(_ context.Context, err *fail.Error) error {
+					reported = true
+					if err.Ignorable {
+						t.Error("The reported error should not be ignorable")
+					}
+					return err
+				})
+			}
+
+			ctx := errorstesting.CreateTestContext(t)
+			ctx.Service = c.server
+			ctx.AddUnaryServerInterceptor(UnaryServerInterceptor(h))
+			ctx.Setup()
+			defer ctx.Teardown()
+
+			_, err := ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+
+			if got, want := status.Code(err), c.code; got != want {
+				t.Errorf("The returned error has error code %v, want %v", got, want)
+			}
+
+			if got, want := reported, c.reported; got != want {
+				t.Errorf("The unmapped code is reported: got %t, want %t", got, want)
+			}
+		})
+	}
+
+	unmapped := UnmappedCodes()
+
+	for _, code := range []int{1051, 1052} {
+		if got, want := unmapped[code], 1; got != want {
+			t.Errorf("Unmapped code %d is recorded %d times, want %d", code, got, want)
+		}
+	}
+
+	if _, ok := unmapped[50]; ok {
+		t.Error("Mapped code should not be recorded")
+	}
+}
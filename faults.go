@@ -0,0 +1,241 @@
+package grpcerrors
+
+import (
+	"errors"
+	"fmt"
+	"math/rand"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// FaultRule is a rule for injecting faults into service methods.
+// A rule is applied to a call when all of its conditions match.
+type FaultRule struct {
+	// Methods are patterns of full methods, same as ForMethods. An empty slice matches any methods.
+	Methods []string
+	// Header is a name of incoming metadata that calls should have. An empty string matches any calls.
+	Header string
+	// HeaderValue is a value of the Header metadata. An empty string matches any values.
+	HeaderValue string
+	// Probability is a probability of applying the rule to matched calls.
+	// Zero always applies the rule as well as 1, so that rules without it are applied to every matched call.
+	Probability float64
+
+	// Latency is added before calling a service method.
+	Latency time.Duration
+	// Panic is a value of a panic raised instead of calling a service method.
+	// The panic is recovered by interceptors with WithPanicRecovery, and otherwise right where it is raised.
+	// Either way it is passed through the error handler chain as PanicError.
+	Panic interface{}
+	// Err is returned instead of calling a service method.
+	Err *fail.Error
+	// Status is returned instead of calling a service method when Err is nil.
+	Status *status.Status
+}
+
+// FaultInjector injects faults into service methods according to rules.
+// It is disabled until Enable is called.
+type FaultInjector struct {
+	rules   []FaultRule
+	enabled int32
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// NewFaultInjector returns a new disabled FaultInjector.
+// It returns an error when a rule has Err without an underlying error, or Probability out of the range from 0 to 1.
+func NewFaultInjector(rules ...FaultRule) (*FaultInjector, error) {
+	for i, r := range rules {
+		if err := r.validate(); err != nil {
+			return nil, fmt.Errorf("grpcerrors: invalid fault rule %d: %v", i, err)
+		}
+	}
+	return &FaultInjector{
+		rules: rules,
+		rand:  rand.New(rand.NewSource(time.Now().UnixNano())),
+	}, nil
+}
+
+func (r *FaultRule) validate() error {
+	if r.Probability < 0 || r.Probability > 1 {
+		return fmt.Errorf("probability %v is not between 0 and 1", r.Probability)
+	}
+	if r.Err != nil && r.Err.Err == nil {
+		return errors.New("fail.Error of Err has no underlying error")
+	}
+	return nil
+}
+
+// Enable starts injecting faults.
+func (f *FaultInjector) Enable() {
+	atomic.StoreInt32(&f.enabled, 1)
+}
+
+// Disable stops injecting faults.
+func (f *FaultInjector) Disable() {
+	atomic.StoreInt32(&f.enabled, 0)
+}
+
+// Enabled returns true if the injector injects faults.
+func (f *FaultInjector) Enabled() bool {
+	return atomic.LoadInt32(&f.enabled) == 1
+}
+
+func (f *FaultInjector) inject(c context.Context, fullMethod string) error {
+	if !f.Enabled() {
+		return nil
+	}
+	for _, r := range f.rules {
+		if !f.match(c, fullMethod, r) {
+			continue
+		}
+		if r.Latency > 0 {
+			t := time.NewTimer(r.Latency)
+			select {
+			case <-t.C:
+			case <-c.Done():
+				t.Stop()
+				return contextStatusError(c.Err())
+			}
+		}
+		if r.Panic != nil {
+			panic(r.Panic)
+		}
+		if r.Err != nil {
+			err := *r.Err
+			return &err
+		}
+		if r.Status != nil {
+			return r.Status.Err()
+		}
+	}
+	return nil
+}
+
+// contextStatusError converts an error of a done context into a status error, as gRPC does for canceled RPCs.
+func contextStatusError(err error) error {
+	switch err {
+	case context.Canceled:
+		return status.Error(codes.Canceled, err.Error())
+	case context.DeadlineExceeded:
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	}
+	return status.Error(codes.Unknown, err.Error())
+}
+
+func (f *FaultInjector) match(c context.Context, fullMethod string, r FaultRule) bool {
+	if len(r.Methods) > 0 {
+		matched := false
+		for _, p := range r.Methods {
+			if matchMethod(p, fullMethod) {
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			return false
+		}
+	}
+	if r.Header != "" {
+		md, _ := metadata.FromIncomingContext(c)
+		values := md[strings.ToLower(r.Header)]
+		if len(values) == 0 {
+			return false
+		}
+		if r.HeaderValue != "" {
+			matched := false
+			for _, v := range values {
+				if v == r.HeaderValue {
+					matched = true
+					break
+				}
+			}
+			if !matched {
+				return false
+			}
+		}
+	}
+	if r.Probability > 0 && r.Probability < 1 {
+		f.mu.Lock()
+		p := f.rand.Float64()
+		f.mu.Unlock()
+		if p >= r.Probability {
+			return false
+		}
+	}
+	return true
+}
+
+type faultInjectionHandler struct {
+	injector *FaultInjector
+}
+
+func (h *faultInjectionHandler) HandleUnaryServerError(c context.Context, req interface{}, info *grpc.UnaryServerInfo, err error) error {
+	return err
+}
+
+func (h *faultInjectionHandler) HandleStreamServerError(c context.Context, req interface{}, resp interface{}, info *grpc.StreamServerInfo, err error) error {
+	return err
+}
+
+// WithFaultInjector returns a new error handler that makes interceptors inject faults with the injector before calling service methods.
+// Injected errors are passed through the error handler chain as if service methods returned them.
+// Injected panics are always recovered as PanicError wrapped with fail.Error, even when WithPanicRecovery is not used,
+// so they never crash a server.
+// It also takes effect when it is wrapped by other handlers such as ForMethods.
+func WithFaultInjector(f *FaultInjector) interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+} {
+	return &faultInjectionHandler{injector: f}
+}
+
+func findFaultInjector(handlers []interface{}, fullMethod string) *FaultInjector {
+	if h, ok := findHandler(handlers, fullMethod, func(h interface{}) bool {
+		_, ok := h.(*faultInjectionHandler)
+		return ok
+	}).(*faultInjectionHandler); ok {
+		return h.injector
+	}
+	return nil
+}
+
+// injectWithRecovery injects a fault, and recovers an injected panic unless an interceptor recovers panics by itself.
+func (f *FaultInjector) injectWithRecovery(c context.Context, fullMethod string, hasRecovery bool) (err error) {
+	if !hasRecovery {
+		defer func() {
+			if r := recover(); r != nil {
+				err = newPanicError(r)
+			}
+		}()
+	}
+	return f.inject(c, fullMethod)
+}
+
+func (f *FaultInjector) unaryHandler(info *grpc.UnaryServerInfo, handler grpc.UnaryHandler, hasRecovery bool) grpc.UnaryHandler {
+	return func(c context.Context, req interface{}) (interface{}, error) {
+		if err := f.injectWithRecovery(c, info.FullMethod, hasRecovery); err != nil {
+			return nil, err
+		}
+		return handler(c, req)
+	}
+}
+
+func (f *FaultInjector) streamHandler(info *grpc.StreamServerInfo, handler grpc.StreamHandler, hasRecovery bool) grpc.StreamHandler {
+	return func(srv interface{}, stream grpc.ServerStream) error {
+		if err := f.injectWithRecovery(stream.Context(), info.FullMethod, hasRecovery); err != nil {
+			return err
+		}
+		return handler(srv, stream)
+	}
+}
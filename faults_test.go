@@ -0,0 +1,245 @@
+package grpcerrors
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/golang/protobuf/ptypes"
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/srvc/grpc-errors/testing"
+	"github.com/srvc/grpc-errors/testing/assert"
+)
+
+func mustNewFaultInjector(t *testing.T, rules ...FaultRule) *FaultInjector {
+	t.Helper()
+	injector, err := NewFaultInjector(rules...)
+	if err != nil {
+		t.Fatalf("Failed to create an injector: %v", err)
+	}
+	return injector
+}
+
+func Test_NewFaultInjector_WhenRulesAreInvalid(t *testing.T) {
+	for _, r := range []FaultRule{
+		{Probability: -0.1},
+		{Probability: 1.5},
+		{Err: &fail.Error{}},
+	} {
+		if _, err := NewFaultInjector(r); err == nil {
+			t.Errorf("NewFaultInjector(%+v) should return an error", r)
+		}
+	}
+}
+
+func Test_UnaryServerInterceptor_WithFaultInjector(t *testing.T) {
+	unavailable, _ := status.New(codes.Unavailable, "injected").WithDetails(&errdetails.RetryInfo{RetryDelay: ptypes.DurationProto(time.Second)})
+
+	cases := []struct {
+		test     string
+		rule     FaultRule
+		disabled bool
+		header   string
+		code     codes.Code
+		latency  time.Duration
+	}{
+		{
+			test:     "disabled",
+			rule:     FaultRule{Err: fail.Wrap(errors.New("injected"), fail.WithCode(50)).(*fail.Error)},
+			disabled: true,
+			code:     codes.OK,
+		},
+		{
+			test: "fail.Error",
+			rule: FaultRule{
+				Methods: []string{"/errorstesting.TestService/*"},
+				Err:     fail.Wrap(errors.New("injected"), fail.WithCode(50)).(*fail.Error),
+			},
+			code: codes.PermissionDenied,
+		},
+		{
+			test: "status with details",
+			rule: FaultRule{Status: unavailable},
+			code: codes.Unavailable,
+		},
+		{
+			test: "panic",
+			rule: FaultRule{Panic: "injected"},
+			code: codes.Internal,
+		},
+		{
+			test:    "latency",
+			rule:    FaultRule{Latency: 20 * time.Millisecond},
+			code:    codes.OK,
+			latency: 20 * time.Millisecond,
+		},
+		{
+			test: "unmatched method",
+			rule: FaultRule{Methods: []string{"/other.Service/*"}, Status: unavailable},
+			code: codes.OK,
+		},
+		{
+			test: "unmatched header",
+			rule: FaultRule{Header: "x-fault", HeaderValue: "unavailable", Status: unavailable},
+			code: codes.OK,
+		},
+		{
+			test:   "matched header",
+			rule:   FaultRule{Header: "X-Fault", HeaderValue: "unavailable", Status: unavailable},
+			header: "unavailable",
+			code:   codes.Unavailable,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			injector := mustNewFaultInjector(t, c.rule)
+			if !c.disabled {
+				injector.Enable()
+			}
+			recorder := errorstesting.NewRecordingHandler("recorder")
+
+			ctx := errorstesting.CreateTestContext(t)
+			ctx.Service = &emptyService{}
+			ctx.AddUnaryServerInterceptor(
+				UnaryServerInterceptor(
+					WithFaultInjector(injector),
+					recorder,
+					WithCodeMap(CodeMap{50: codes.PermissionDenied}),
+					WithPanicRecovery(),
+				),
+			)
+			ctx.Setup()
+			defer ctx.Teardown()
+
+			cctx := context.Background()
+			if c.header != "" {
+				cctx = metadata.AppendToOutgoingContext(cctx, "x-fault", c.header)
+			}
+			start := time.Now()
+			_, err := ctx.Client.EmptyCall(cctx, &errorstesting.Empty{})
+
+			assert.Code(t, err, c.code)
+
+			if got, want := recorder.Called(), c.code != codes.OK; got != want {
+				t.Errorf("Error handlers are called: got %t, want %t", got, want)
+			}
+
+			if c.code == codes.Unavailable {
+				assert.HasDetail(t, err, &errdetails.RetryInfo{})
+			}
+
+			if d := time.Since(start); d < c.latency {
+				t.Errorf("The request took %v, want at least %v", d, c.latency)
+			}
+		})
+	}
+}
+
+func Test_StreamServerInterceptor_WithFaultInjector(t *testing.T) {
+	injector := mustNewFaultInjector(t, FaultRule{
+		Methods: []string{"/errorstesting.TestService/BidiStreamCall"},
+		Err:     fail.Wrap(errors.New("injected"), fail.WithCode(50)).(*fail.Error),
+	})
+	injector.Enable()
+
+	ctx := errorstesting.CreateTestContext(t)
+	ctx.Service = &streamFailService{}
+	ctx.AddStreamServerInterceptor(
+		StreamServerInterceptor(
+			WithFaultInjector(injector),
+			WithCodeMap(CodeMap{50: codes.ResourceExhausted}),
+		),
+	)
+	ctx.Setup()
+	defer ctx.Teardown()
+
+	stream, err := ctx.Client.BidiStreamCall(context.Background())
+	if err == nil {
+		_, err = stream.Recv()
+	}
+
+	assert.Code(t, err, codes.ResourceExhausted)
+	assert.Message(t, err, "injected")
+}
+
+func Test_UnaryServerInterceptor_WithFaultInjector_WithoutPanicRecovery(t *testing.T) {
+	injector := mustNewFaultInjector(t, FaultRule{Panic: "injected"})
+	injector.Enable()
+	recorder := errorstesting.NewRecordingHandler("recorder")
+
+	ctx := errorstesting.CreateTestContext(t)
+	ctx.Service = &emptyService{}
+	ctx.AddUnaryServerInterceptor(
+		UnaryServerInterceptor(
+			ForMethods([]string{"/errorstesting.TestService/*"}, WithFaultInjector(injector)),
+			recorder,
+		),
+	)
+	ctx.Setup()
+	defer ctx.Teardown()
+
+	for i := 0; i < 2; i++ {
+		_, err := ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+
+		if err == nil {
+			t.Fatal("The request should return an error")
+		}
+	}
+
+	if got, want := recorder.Count(), 2; got != want {
+		t.Fatalf("Error handlers are called %d times, want %d", got, want)
+	}
+
+	call, _ := recorder.LastCall()
+	fErr := fail.Unwrap(call.Err)
+	if fErr == nil {
+		t.Fatalf("The injected panic should be wrapped with fail.Error: %v", call.Err)
+	}
+
+	if pErr, ok := fErr.Err.(*PanicError); !ok {
+		t.Errorf("The injected panic should be PanicError: %v", fErr.Err)
+	} else if got, want := pErr.Value, "injected"; got != want {
+		t.Errorf("The injected panic has value %v, want %v", got, want)
+	}
+}
+
+func Test_FaultInjector_Panic(t *testing.T) {
+	injector := mustNewFaultInjector(t, FaultRule{Panic: "injected"})
+	injector.Enable()
+
+	info := &grpc.UnaryServerInfo{FullMethod: "/errorstesting.TestService/EmptyCall"}
+	handler := injector.unaryHandler(info, func(context.Context, interface{}) (interface{}, error) {
+		t.Error("The service method should not be called")
+		return nil, nil
+	}, true)
+
+	defer func() {
+		if got, want := recover(), interface{}("injected"); got != want {
+			t.Errorf("The handler panics with %v, want %v", got, want)
+		}
+	}()
+	handler(context.Background(), &errorstesting.Empty{})
+	t.Error("The handler should panic")
+}
+
+func Test_FaultInjector_LatencyWithDoneContext(t *testing.T) {
+	injector := mustNewFaultInjector(t, FaultRule{Latency: time.Second})
+	injector.Enable()
+
+	canceled, cancel := context.WithCancel(context.Background())
+	cancel()
+	expired, cancel := context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+
+	for c, code := range map[context.Context]codes.Code{canceled: codes.Canceled, expired: codes.DeadlineExceeded} {
+		assert.Code(t, injector.inject(c, "/errorstesting.TestService/EmptyCall"), code)
+	}
+}
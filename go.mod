@@ -1,15 +1,17 @@
 module github.com/srvc/grpc-errors
 
-go 1.12
+go 1.14
 
 require (
 	github.com/golang/glog v0.0.0-20160126235308-23def4e6c14b // indirect
 	github.com/golang/protobuf v1.1.0
+	github.com/pelletier/go-toml v1.9.5
 	github.com/srvc/fail/v4 v4.1.1
 	golang.org/x/net v0.0.0-20180816102801-aaf60122140d
 	golang.org/x/sync v0.0.0-20190412183630-56d357773e84 // indirect
 	golang.org/x/sys v0.0.0-20180816055513-1c9583448a9c // indirect
 	golang.org/x/text v0.3.0 // indirect
-	google.golang.org/genproto v0.0.0-20180817151627-c66870c02cf8 // indirect
+	google.golang.org/genproto v0.0.0-20180817151627-c66870c02cf8
 	google.golang.org/grpc v1.14.0
+	gopkg.in/yaml.v3 v3.0.1
 )
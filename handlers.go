@@ -18,12 +18,25 @@ type StreamServerErrorHandler interface {
 	HandleStreamServerError(context.Context, interface{}, interface{}, *grpc.StreamServerInfo, error) error
 }
 
+// UnaryClientErrorHandler is the interface that can handle errors on a gRPC unary client
+type UnaryClientErrorHandler interface {
+	HandleUnaryClientError(context.Context, string, interface{}, interface{}, []grpc.CallOption, error) error
+}
+
+// StreamClientErrorHandler is the interface that can handle errors on a gRPC stream client
+type StreamClientErrorHandler interface {
+	HandleStreamClientError(context.Context, interface{}, interface{}, *grpc.StreamDesc, string, []grpc.CallOption, error) error
+}
+
 // ErrorHandlerFunc is a function that called by interceptors when specified erorrs are detected.
 type ErrorHandlerFunc func(context.Context, error) error
 
 // FailHandlerFunc is a function that called by interceptors when specified application erorrs are detected.
 type FailHandlerFunc func(context.Context, *fail.Error) error
 
+// StatusHandlerFunc is a function that called by client interceptors when errors with gRPC statuses are detected.
+type StatusHandlerFunc func(context.Context, *status.Status) error
+
 type failHandler struct {
 	f FailHandlerFunc
 }
@@ -97,28 +110,80 @@ func WithReportableErrorHandler(f FailHandlerFunc) interface {
 type CodeMap map[interface{}]codes.Code
 
 // WithCodeMap returns a new error handler function for mapping status codes to gRPC's one.
-func WithCodeMap(m CodeMap) interface {
+// Messages returned from detailFns are attached to built statuses as details.
+// When they cannot be marshaled, statuses are sent without details and the failure is logged with grpclog.
+func WithCodeMap(m CodeMap, detailFns ...StatusDetailsFunc) interface {
 	UnaryServerErrorHandler
 	StreamServerErrorHandler
 } {
 	return WithFailHandler(func(c context.Context, err *fail.Error) error {
-		if c, ok := m[err.Code]; ok {
-			return status.Error(c, err.Error())
+		if code, ok := m[err.Code]; ok {
+			return newStatusError(c, code, err.Error(), err, detailFns)
 		}
 		return err
 	})
 }
 
+// Inverse returns an InverseCodeMap that maps gRPC's `codes.Code`s back to status codes.
+// gRPC's codes that are mapped from more than one status code are omitted since they cannot be restored uniquely.
+func (m CodeMap) Inverse() InverseCodeMap {
+	inv := make(InverseCodeMap, len(m))
+	dup := make(map[codes.Code]bool)
+	for code, grpcCode := range m {
+		if _, ok := inv[grpcCode]; ok {
+			dup[grpcCode] = true
+		}
+		inv[grpcCode] = code
+	}
+	for grpcCode := range dup {
+		delete(inv, grpcCode)
+	}
+	return inv
+}
+
+// InverseCodeMap maps gRPC's `codes.Code`s to any status codes.
+type InverseCodeMap map[codes.Code]interface{}
+
+// WithInverseCodeMap returns a new error handler function for clients that restores fail.Error from gRPC statuses.
+// Messages, ignorable flags, tags and params are restored from errorspb.FailDetail when a status has it.
+// The restored error wraps an error of the gRPC status, and its Error method returns the status message.
+// A code is restored from errorspb.FailDetail when it matches one of values of the map, or from a gRPC's code.
+// It returns an original error when a status has neither errorspb.FailDetail nor a gRPC's code contained in the map.
+func WithInverseCodeMap(m InverseCodeMap) interface {
+	UnaryClientErrorHandler
+	StreamClientErrorHandler
+} {
+	values := make([]interface{}, 0, len(m))
+	for _, code := range m {
+		values = append(values, code)
+	}
+	return WithStatusHandler(func(c context.Context, st *status.Status) error {
+		code, ok := m[st.Code()]
+		if detail := failDetailFromStatus(st); detail != nil {
+			err, matched := restoreFail(st, detail, values)
+			if !matched && ok {
+				err.Code = code
+			}
+			return fail.Wrap(err)
+		}
+		if ok {
+			return fail.Wrap(&fail.Error{Err: newRestoredStatusError(st, nil), Code: code})
+		}
+		return st.Err()
+	})
+}
+
 // CodeMapFunc returns gRPC's `codes.Code`s from given any codes.
 type CodeMapFunc func(code interface{}) codes.Code
 
 // WithCodeMapper returns a new error handler function for mapping status codes to gRPC's one with given function.
-func WithCodeMapper(mapFn CodeMapFunc) interface {
+// Messages returned from detailFns are attached to built statuses as details.
+func WithCodeMapper(mapFn CodeMapFunc, detailFns ...StatusDetailsFunc) interface {
 	UnaryServerErrorHandler
 	StreamServerErrorHandler
 } {
 	return WithFailHandler(func(c context.Context, err *fail.Error) error {
-		return status.Error(mapFn(err.Code), err.Error())
+		return newStatusError(c, mapFn(err.Code), err.Error(), err, detailFns)
 	})
 }
 
@@ -134,3 +199,30 @@ func WithGrpcStatusUnwrapper() interface {
 		return err
 	})
 }
+
+type statusHandler struct {
+	f StatusHandlerFunc
+}
+
+func (h *statusHandler) HandleUnaryClientError(c context.Context, method string, req, reply interface{}, opts []grpc.CallOption, err error) error {
+	return h.handleError(c, err)
+}
+
+func (h *statusHandler) HandleStreamClientError(c context.Context, req, resp interface{}, desc *grpc.StreamDesc, method string, opts []grpc.CallOption, err error) error {
+	return h.handleError(c, err)
+}
+
+func (h *statusHandler) handleError(c context.Context, err error) error {
+	if st, ok := status.FromError(err); ok {
+		return h.f(c, st)
+	}
+	return err
+}
+
+// WithStatusHandler returns a new error handler function for handling errors that have gRPC statuses on clients.
+func WithStatusHandler(f StatusHandlerFunc) interface {
+	UnaryClientErrorHandler
+	StreamClientErrorHandler
+} {
+	return &statusHandler{f: f}
+}
@@ -0,0 +1,134 @@
+package grpcerrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+
+	"github.com/golang/protobuf/jsonpb"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/srvc/grpc-errors/errorspb"
+)
+
+// HTTPStatusFromCode returns an HTTP status code corresponding to a gRPC code in the same way as grpc-gateway.
+func HTTPStatusFromCode(code codes.Code) int {
+	switch code {
+	case codes.OK:
+		return http.StatusOK
+	case codes.Canceled:
+		return 499
+	case codes.InvalidArgument, codes.FailedPrecondition, codes.OutOfRange:
+		return http.StatusBadRequest
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	case codes.NotFound:
+		return http.StatusNotFound
+	case codes.AlreadyExists, codes.Aborted:
+		return http.StatusConflict
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	case codes.Unimplemented:
+		return http.StatusNotImplemented
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	}
+	return http.StatusInternalServerError
+}
+
+// HTTPErrorBody is a JSON envelope of error responses written by HTTPErrorWriter.
+type HTTPErrorBody struct {
+	Error HTTPError `json:"error"`
+}
+
+// HTTPError is an error in HTTPErrorBody.
+type HTTPError struct {
+	// Code is an application-specific status code carried by errorspb.FailDetail.
+	Code string `json:"code,omitempty"`
+	// GrpcCode is a name of a gRPC code like "NOT_FOUND".
+	GrpcCode string `json:"grpc_code"`
+	// Message is a message of a gRPC status.
+	Message string `json:"message"`
+	// Details is details of a gRPC status encoded as JSON with "@type" fields.
+	Details []json.RawMessage `json:"details,omitempty"`
+	// RequestID is an identifier of a request.
+	RequestID string `json:"request_id,omitempty"`
+}
+
+// HTTPErrorWriter writes errors as JSON error responses.
+//
+// It can be used as an error handler of grpc-gateway:
+//
+//	ew := &grpcerrors.HTTPErrorWriter{}
+//	mux := runtime.NewServeMux(runtime.WithErrorHandler(
+//		func(ctx context.Context, _ *runtime.ServeMux, _ runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+//			ew.WriteError(ctx, w, r, err)
+//		},
+//	))
+type HTTPErrorWriter struct {
+	// StatusMap maps string representations of application status codes to HTTP status codes.
+	// HTTPStatusFromCode is used for codes that are not contained.
+	StatusMap map[string]int
+	// RequestID returns an identifier of a request. A correlation ID in errorspb.FailDetail takes precedence.
+	// The "X-Request-Id" header is used when it is nil.
+	RequestID func(*http.Request) string
+}
+
+// WriteError writes an error response from a gRPC status of err.
+func (ew *HTTPErrorWriter) WriteError(c context.Context, w http.ResponseWriter, r *http.Request, err error) {
+	st := status.Convert(err)
+	body := HTTPErrorBody{
+		Error: HTTPError{
+			GrpcCode: grpcCodeName(st.Code()),
+			Message:  st.Message(),
+		},
+	}
+
+	m := &jsonpb.Marshaler{}
+	for _, any := range st.Proto().GetDetails() {
+		var buf bytes.Buffer
+		if err := m.Marshal(&buf, any); err == nil {
+			body.Error.Details = append(body.Error.Details, buf.Bytes())
+		}
+	}
+
+	for _, d := range st.Details() {
+		if detail, ok := d.(*errorspb.FailDetail); ok {
+			body.Error.Code = detail.Code
+			body.Error.RequestID = detail.CorrelationId
+		}
+	}
+	if body.Error.RequestID == "" {
+		body.Error.RequestID = ew.requestID(r)
+	}
+
+	httpStatus, ok := ew.StatusMap[body.Error.Code]
+	if !ok || body.Error.Code == "" {
+		httpStatus = HTTPStatusFromCode(st.Code())
+	}
+
+	data, mErr := json.Marshal(body)
+	if mErr != nil {
+		http.Error(w, mErr.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	w.Write(data)
+}
+
+func (ew *HTTPErrorWriter) requestID(r *http.Request) string {
+	if ew.RequestID != nil {
+		return ew.RequestID(r)
+	}
+	if r == nil {
+		return ""
+	}
+	return r.Header.Get("X-Request-Id")
+}
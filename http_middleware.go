@@ -0,0 +1,87 @@
+package grpcerrors
+
+import (
+	"net/http"
+	"strings"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// HTTPHandlerFunc is an HTTP handler function that returns an error.
+type HTTPHandlerFunc func(http.ResponseWriter, *http.Request) error
+
+// HTTPMiddleware returns a new middleware that handles errors returned from HTTP handlers with error handlers for unary servers.
+// Error handlers receive a *http.Request as a request and a route as a full method,
+// and request headers are available as incoming metadata.
+// A route should be a fixed name or pattern of handled paths, such as "/users/{id}", rather than a path of each request,
+// since handlers such as WithErrorCounter use it as a label.
+// Errors returned from error handlers are written by ew, or a zero HTTPErrorWriter when ew is nil.
+// They are only passed to error handlers, and not written, when a handler has already written a header or a body.
+func HTTPMiddleware(ew *HTTPErrorWriter, handlers ...UnaryServerErrorHandler) func(route string, h HTTPHandlerFunc) http.Handler {
+	if ew == nil {
+		ew = &HTTPErrorWriter{}
+	}
+	errHandler := composeUnaryServerErrorHandlers(handlers)
+	hs := unaryServerHandlers(handlers)
+	return func(route string, h HTTPHandlerFunc) http.Handler {
+		recovery := hasPanicRecovery(hs, route)
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ctx := metadata.NewIncomingContext(r.Context(), metadataFromHeader(r.Header))
+			r = r.WithContext(ctx)
+
+			tw := &trackingResponseWriter{ResponseWriter: w}
+			var err error
+			if recovery {
+				err = invokeHTTPHandlerWithRecovery(tw, r, h)
+			} else {
+				err = h(tw, r)
+			}
+
+			info := &grpc.UnaryServerInfo{FullMethod: route}
+			if err = errHandler.HandleUnaryServerError(ctx, r, info, err); err != nil && !tw.wroteHeader {
+				ew.WriteError(ctx, w, r, err)
+			}
+		})
+	}
+}
+
+// trackingResponseWriter records whether a header has been written, so that error responses do not corrupt partial responses.
+type trackingResponseWriter struct {
+	http.ResponseWriter
+	wroteHeader bool
+}
+
+func (w *trackingResponseWriter) WriteHeader(code int) {
+	w.wroteHeader = true
+	w.ResponseWriter.WriteHeader(code)
+}
+
+func (w *trackingResponseWriter) Write(b []byte) (int, error) {
+	w.wroteHeader = true
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *trackingResponseWriter) Flush() {
+	if f, ok := w.ResponseWriter.(http.Flusher); ok {
+		w.wroteHeader = true
+		f.Flush()
+	}
+}
+
+func invokeHTTPHandlerWithRecovery(w http.ResponseWriter, r *http.Request, h HTTPHandlerFunc) (err error) {
+	defer func() {
+		if rec := recover(); rec != nil {
+			err = newPanicError(rec)
+		}
+	}()
+	return h(w, r)
+}
+
+func metadataFromHeader(header http.Header) metadata.MD {
+	md := make(metadata.MD, len(header))
+	for k, vs := range header {
+		md[strings.ToLower(k)] = vs
+	}
+	return md
+}
@@ -0,0 +1,221 @@
+package grpcerrors
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func Test_HTTPErrorWriter_WriteError(t *testing.T) {
+	mapper := WithCodeMap(
+		CodeMap{"invalid_user": codes.InvalidArgument, "rate_limited": codes.ResourceExhausted},
+		FailDetails(func(context.Context) string { return "correlation-id" }),
+		StandardDetails(),
+	)
+	mapError := func(err error) error {
+		return mapper.HandleUnaryServerError(context.Background(), nil, &grpc.UnaryServerInfo{}, err)
+	}
+
+	cases := []struct {
+		test      string
+		err       error
+		status    int
+		code      string
+		grpcCode  string
+		details   int
+		requestID string
+	}{
+		{
+			test:      "error with details",
+			err:       mapError(fail.Wrap(errors.New("invalid user"), fail.WithCode("invalid_user"), WithFieldViolation("name", "required"))),
+			status:    http.StatusBadRequest,
+			code:      "invalid_user",
+			grpcCode:  "INVALID_ARGUMENT",
+			details:   2,
+			requestID: "correlation-id",
+		},
+		{
+			test:      "error with HTTP status mapped by application code",
+			err:       mapError(fail.Wrap(errors.New("rate limited"), fail.WithCode("rate_limited"))),
+			status:    http.StatusServiceUnavailable,
+			code:      "rate_limited",
+			grpcCode:  "RESOURCE_EXHAUSTED",
+			details:   1,
+			requestID: "correlation-id",
+		},
+		{
+			test:      "error without details",
+			err:       status.Error(codes.NotFound, "not found"),
+			status:    http.StatusNotFound,
+			grpcCode:  "NOT_FOUND",
+			requestID: "header-request-id",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			ew := &HTTPErrorWriter{StatusMap: map[string]int{"rate_limited": http.StatusServiceUnavailable}}
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+			r.Header.Set("X-Request-Id", "header-request-id")
+
+			ew.WriteError(context.Background(), w, r, c.err)
+
+			if got, want := w.Code, c.status; got != want {
+				t.Errorf("Written status is %d, want %d", got, want)
+			}
+
+			if got, want := w.Header().Get("Content-Type"), "application/json"; got != want {
+				t.Errorf("Written content type is %q, want %q", got, want)
+			}
+
+			var body HTTPErrorBody
+			if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+				t.Fatalf("Failed to decode a body: %v", err)
+			}
+
+			if got, want := body.Error.Code, c.code; got != want {
+				t.Errorf("Written code is %q, want %q", got, want)
+			}
+
+			if got, want := body.Error.GrpcCode, c.grpcCode; got != want {
+				t.Errorf("Written gRPC code is %q, want %q", got, want)
+			}
+
+			if got, want := len(body.Error.Details), c.details; got != want {
+				t.Errorf("Written %d details, want %d", got, want)
+			}
+
+			if got, want := body.Error.RequestID, c.requestID; got != want {
+				t.Errorf("Written request id is %q, want %q", got, want)
+			}
+		})
+	}
+}
+
+func Test_HTTPMiddleware(t *testing.T) {
+	var reported *fail.Error
+
+	middleware := HTTPMiddleware(
+		nil,
+		ForMethods([]string{"/users/*"}, WithReportableErrorHandler(func(_ context.Context, err *fail.Error) error {
+			reported = err
+			return err
+		})),
+		WithCodeMap(CodeMap{50: codes.PermissionDenied}, LocalizedMessageDetails(MapMessageCatalog{"ja": {"50": "権限がありません"}}, "en")),
+		WithPanicRecovery(),
+	)
+
+	cases := []struct {
+		test     string
+		route    string
+		handler  HTTPHandlerFunc
+		status   int
+		body     interface{}
+		reported bool
+	}{
+		{
+			test:  "no errors",
+			route: "/users/{id}",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				w.WriteHeader(http.StatusNoContent)
+				return nil
+			},
+			status: http.StatusNoContent,
+		},
+		{
+			test:  "error with code that contained CodeMap",
+			route: "/users/{id}",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return fail.Wrap(errors.New("permission denied"), fail.WithCode(50))
+			},
+			status:   http.StatusForbidden,
+			reported: true,
+		},
+		{
+			test:  "error on a path not matched",
+			route: "/healthz",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				return fail.Wrap(errors.New("permission denied"), fail.WithCode(50))
+			},
+			status: http.StatusForbidden,
+		},
+		{
+			test:  "error after writing a header",
+			route: "/users/{id}",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				w.WriteHeader(http.StatusAccepted)
+				return fail.Wrap(errors.New("permission denied"), fail.WithCode(50))
+			},
+			status:   http.StatusAccepted,
+			body:     "",
+			reported: true,
+		},
+		{
+			test:  "error after writing a body",
+			route: "/users/{id}",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				w.Write([]byte("partial"))
+				return fail.Wrap(errors.New("permission denied"), fail.WithCode(50))
+			},
+			status:   http.StatusOK,
+			body:     "partial",
+			reported: true,
+		},
+		{
+			test:  "panic",
+			route: "/users/{id}",
+			handler: func(w http.ResponseWriter, r *http.Request) error {
+				panic("This handler always panics")
+			},
+			status:   http.StatusInternalServerError,
+			reported: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			reported = nil
+
+			w := httptest.NewRecorder()
+			r := httptest.NewRequest(http.MethodGet, strings.Replace(c.route, "{id}", "1", 1), nil)
+			r.Header.Set("Accept-Language", "ja")
+
+			middleware(c.route, c.handler).ServeHTTP(w, r)
+
+			if got, want := w.Code, c.status; got != want {
+				t.Errorf("Written status is %d, want %d", got, want)
+			}
+
+			if got, want := reported != nil, c.reported; got != want {
+				t.Errorf("The error is reported: got %t, want %t", got, want)
+			}
+
+			if c.body != nil {
+				if got, want := w.Body.String(), c.body; got != want {
+					t.Errorf("Written body is %q, want %q", got, want)
+				}
+			}
+
+			if c.status == http.StatusForbidden {
+				var body HTTPErrorBody
+				if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+					t.Fatalf("Failed to decode a body: %v", err)
+				}
+
+				if got, want := len(body.Error.Details), 1; got != want {
+					t.Errorf("Written %d details, want %d", got, want)
+				}
+			}
+		})
+	}
+}
@@ -1,6 +1,9 @@
 package grpcerrors
 
 import (
+	"io"
+	"sync"
+
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 )
@@ -8,40 +11,175 @@ import (
 // UnaryServerInterceptor returns a new unary server interceptor to handle errors
 func UnaryServerInterceptor(handlers ...UnaryServerErrorHandler) grpc.UnaryServerInterceptor {
 	errHandler := composeUnaryServerErrorHandlers(handlers)
+	features := newMethodFeaturesCache(unaryServerHandlers(handlers))
 	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
-		resp, err := handler(ctx, req)
+		f := features.get(info.FullMethod)
+		if f.faults != nil {
+			handler = f.faults.unaryHandler(info, handler, f.recovery)
+		}
+		var resp interface{}
+		var err error
+		if f.recovery {
+			resp, err = invokeUnaryHandlerWithRecovery(ctx, req, handler)
+		} else {
+			resp, err = handler(ctx, req)
+		}
 		return resp, errHandler.HandleUnaryServerError(ctx, req, info, err)
 	}
 }
 
-// StreamServerInterceptor returns a new streaming server interceptor to handle errors
+func invokeUnaryHandlerWithRecovery(ctx context.Context, req interface{}, handler grpc.UnaryHandler) (resp interface{}, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			resp, err = nil, newPanicError(r)
+		}
+	}()
+	return handler(ctx, req)
+}
+
+// StreamServerInterceptor returns a new streaming server interceptor to handle errors.
+// Error handlers receive the last received and sent messages as a request and a response,
+// and a StreamRecord is available with StreamRecordFromContext when WithStreamRecorder is used.
 func StreamServerInterceptor(handlers ...StreamServerErrorHandler) grpc.StreamServerInterceptor {
 	errHandler := composeStreamServerErrorHandlers(handlers)
+	features := newMethodFeaturesCache(streamServerHandlers(handlers))
 	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
-		newStream := &recordableServerStream{ServerStream: stream}
-		err := handler(srv, newStream)
+		f := features.get(info.FullMethod)
+		if f.faults != nil {
+			handler = f.faults.streamHandler(info, handler, f.recovery)
+		}
+		newStream := &recordableServerStream{ServerStream: stream, recorder: newStreamRecorder(f.streamRecorder)}
+		var err error
+		if f.recovery {
+			err = invokeStreamHandlerWithRecovery(srv, newStream, handler)
+		} else {
+			err = handler(srv, newStream)
+		}
+		if err == nil {
+			return nil
+		}
+		if newStream.recorder == nil {
+			return errHandler.HandleStreamServerError(newStream.Context(), newStream.request, newStream.response, info, err)
+		}
+		record := newStream.recorder.snapshot()
 		return errHandler.HandleStreamServerError(
-			newStream.Context(),
-			newStream.request,
-			newStream.response,
+			context.WithValue(newStream.Context(), streamRecordKey{}, record),
+			record.Received.Last(),
+			record.Sent.Last(),
 			info,
 			err,
 		)
 	}
 }
 
+func invokeStreamHandlerWithRecovery(srv interface{}, stream grpc.ServerStream, handler grpc.StreamHandler) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = newPanicError(r)
+		}
+	}()
+	return handler(srv, stream)
+}
+
 type recordableServerStream struct {
 	grpc.ServerStream
+	// recorder is nil unless WithStreamRecorder is used, and then only the last messages are kept.
+	recorder *streamRecorder
 	request  interface{}
 	response interface{}
 }
 
 func (s *recordableServerStream) SendMsg(m interface{}) error {
-	s.response = m
-	return s.ServerStream.SendMsg(m)
+	err := s.ServerStream.SendMsg(m)
+	if err == nil {
+		if s.recorder == nil {
+			s.response = m
+		} else {
+			s.recorder.recordSent(m)
+		}
+	}
+	return err
 }
 
 func (s *recordableServerStream) RecvMsg(m interface{}) error {
+	err := s.ServerStream.RecvMsg(m)
+	if err == nil {
+		if s.recorder == nil {
+			s.request = m
+		} else {
+			s.recorder.recordReceived(m)
+		}
+	}
+	return err
+}
+
+// UnaryClientInterceptor returns a new unary client interceptor to handle errors
+func UnaryClientInterceptor(handlers ...UnaryClientErrorHandler) grpc.UnaryClientInterceptor {
+	errHandler := composeUnaryClientErrorHandlers(handlers)
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		err := invoker(ctx, method, req, reply, cc, opts...)
+		return errHandler.HandleUnaryClientError(ctx, method, req, reply, opts, err)
+	}
+}
+
+// StreamClientInterceptor returns a new streaming client interceptor to handle errors
+func StreamClientInterceptor(handlers ...StreamClientErrorHandler) grpc.StreamClientInterceptor {
+	errHandler := composeStreamClientErrorHandlers(handlers)
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		stream, err := streamer(ctx, desc, cc, method, opts...)
+		if err != nil {
+			return nil, errHandler.HandleStreamClientError(ctx, nil, nil, desc, method, opts, err)
+		}
+		return &recordableClientStream{
+			ClientStream: stream,
+			ctx:          ctx,
+			desc:         desc,
+			method:       method,
+			opts:         opts,
+			errHandler:   errHandler,
+		}, nil
+	}
+}
+
+type recordableClientStream struct {
+	grpc.ClientStream
+	ctx        context.Context
+	desc       *grpc.StreamDesc
+	method     string
+	opts       []grpc.CallOption
+	errHandler StreamClientErrorHandler
+
+	// mu guards request and response since SendMsg and RecvMsg can be called concurrently.
+	mu       sync.Mutex
+	request  interface{}
+	response interface{}
+}
+
+func (s *recordableClientStream) SendMsg(m interface{}) error {
+	s.mu.Lock()
 	s.request = m
-	return s.ServerStream.RecvMsg(m)
+	s.mu.Unlock()
+	return s.handleError(s.ClientStream.SendMsg(m))
+}
+
+func (s *recordableClientStream) RecvMsg(m interface{}) error {
+	s.mu.Lock()
+	s.response = m
+	s.mu.Unlock()
+	return s.handleError(s.ClientStream.RecvMsg(m))
+}
+
+func (s *recordableClientStream) CloseSend() error {
+	return s.handleError(s.ClientStream.CloseSend())
+}
+
+func (s *recordableClientStream) handleError(err error) error {
+	// io.EOF reports the end of a stream, and the actual status is returned from RecvMsg.
+	if err == io.EOF {
+		return err
+	}
+	s.mu.Lock()
+	req, resp := s.request, s.response
+	s.mu.Unlock()
+	return s.errHandler.HandleStreamClientError(s.ctx, req, resp, s.desc, s.method, s.opts, err)
 }
@@ -2,7 +2,9 @@ package grpcerrors
 
 import (
 	"errors"
+	"io"
 	"reflect"
+	"sync"
 	"testing"
 
 	"golang.org/x/net/context"
@@ -17,6 +19,7 @@ import (
 // Sevice implementations
 // ================================================
 type emptyService struct {
+	errorstesting.UnimplementedTestServiceServer
 }
 
 func (s *emptyService) EmptyCall(context.Context, *errorstesting.Empty) (*errorstesting.Empty, error) {
@@ -24,6 +27,7 @@ func (s *emptyService) EmptyCall(context.Context, *errorstesting.Empty) (*errors
 }
 
 type errorService struct {
+	errorstesting.UnimplementedTestServiceServer
 }
 
 func (s *errorService) EmptyCall(context.Context, *errorstesting.Empty) (*errorstesting.Empty, error) {
@@ -31,6 +35,7 @@ func (s *errorService) EmptyCall(context.Context, *errorstesting.Empty) (*errors
 }
 
 type failService struct {
+	errorstesting.UnimplementedTestServiceServer
 }
 
 func (s *failService) EmptyCall(context.Context, *errorstesting.Empty) (*errorstesting.Empty, error) {
@@ -38,6 +43,7 @@ func (s *failService) EmptyCall(context.Context, *errorstesting.Empty) (*errorst
 }
 
 type ignoredErrorService struct {
+	errorstesting.UnimplementedTestServiceServer
 }
 
 func (s *ignoredErrorService) EmptyCall(context.Context, *errorstesting.Empty) (*errorstesting.Empty, error) {
@@ -45,6 +51,7 @@ func (s *ignoredErrorService) EmptyCall(context.Context, *errorstesting.Empty) (
 }
 
 type errorWithStatusService struct {
+	errorstesting.UnimplementedTestServiceServer
 	Code int
 }
 
@@ -53,6 +60,7 @@ func (s *errorWithStatusService) EmptyCall(context.Context, *errorstesting.Empty
 }
 
 type errorWithGrpcStatusService struct {
+	errorstesting.UnimplementedTestServiceServer
 	Code codes.Code
 }
 
@@ -60,6 +68,69 @@ func (s *errorWithGrpcStatusService) EmptyCall(context.Context, *errorstesting.E
 	return nil, fail.Wrap(status.Error(s.Code, "This error has a gRPC status code"))
 }
 
+type errorWithAnnotationsService struct {
+	errorstesting.UnimplementedTestServiceServer
+	Code int
+}
+
+func (s *errorWithAnnotationsService) EmptyCall(context.Context, *errorstesting.Empty) (*errorstesting.Empty, error) {
+	return nil, fail.Wrap(
+		errors.New("This error has annotations"),
+		fail.WithCode(s.Code),
+		fail.WithMessage("annotated"),
+		fail.WithIgnorable(),
+		fail.WithTags("tag1", "tag2"),
+		fail.WithParam("id", 1),
+	)
+}
+
+type panicService struct {
+	errorstesting.UnimplementedTestServiceServer
+}
+
+func (s *panicService) EmptyCall(context.Context, *errorstesting.Empty) (*errorstesting.Empty, error) {
+	panic("This service always panics")
+}
+
+type streamFailService struct {
+	errorstesting.UnimplementedTestServiceServer
+}
+
+func (s *streamFailService) ServerStreamCall(_ *errorstesting.Empty, stream errorstesting.TestService_ServerStreamCallServer) error {
+	for i := 0; i < 2; i++ {
+		if err := stream.Send(&errorstesting.Empty{}); err != nil {
+			return err
+		}
+	}
+	return fail.Wrap(fail.New("This stream always fails"), fail.WithCode(50))
+}
+
+func (s *streamFailService) ClientStreamCall(stream errorstesting.TestService_ClientStreamCallServer) error {
+	for {
+		if _, err := stream.Recv(); err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+	}
+	return fail.Wrap(fail.New("This stream always fails"), fail.WithCode(50))
+}
+
+func (s *streamFailService) BidiStreamCall(stream errorstesting.TestService_BidiStreamCallServer) error {
+	for {
+		req, err := stream.Recv()
+		if err == io.EOF {
+			break
+		} else if err != nil {
+			return err
+		}
+		if err := stream.Send(req); err != nil {
+			return err
+		}
+	}
+	return fail.Wrap(fail.New("This stream always fails"), fail.WithCode(50))
+}
+
 // Testings
 // ================================================
 func Test_UnaryServerInterceptor(t *testing.T) {
@@ -283,3 +354,510 @@ func Test_UnaryServerInterceptor_WithUnaryServerReportableErrorHandler_WhenAnErr
 		t.Error("Report error handler should be called")
 	}
 }
+
+// Testings for UnaryClientErrorHandler
+// ================================================
+func Test_UnaryClientInterceptor(t *testing.T) {
+	cases := []struct {
+		test    string
+		server  errorstesting.TestServiceServer
+		code    codes.Code
+		errored bool
+		handled bool
+	}{
+		{
+			test:   "no errors",
+			server: &emptyService{},
+			code:   codes.OK,
+		},
+		{
+			test:    "error without gRPC's code",
+			server:  &errorService{},
+			code:    codes.Unknown,
+			errored: true,
+			handled: true,
+		},
+		{
+			test:    "error with gRPC's code",
+			server:  &errorWithGrpcStatusService{Code: codes.AlreadyExists},
+			code:    codes.AlreadyExists,
+			errored: true,
+			handled: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			var handled bool
+
+			ctx := errorstesting.CreateTestContext(t)
+			ctx.Service = c.server
+			ctx.AddUnaryServerInterceptor(UnaryServerInterceptor(WithGrpcStatusUnwrapper()))
+			ctx.AddUnaryClientInterceptor(
+				UnaryClientInterceptor(
+					WithStatusHandler(func(_ context.Context, st *status.Status) error {
+						handled = true
+						if got, want := st.Code(), c.code; got != want {
+							t.Errorf("Received status has error code %v, want %v", got, want)
+						}
+						return fail.Wrap(st.Err(), fail.WithCode(int(st.Code())))
+					}),
+				),
+			)
+			ctx.Setup()
+			defer ctx.Teardown()
+
+			resp, err := ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+
+			if c.errored {
+				if resp != nil {
+					t.Error("The request should not return a response")
+				}
+
+				if fErr := fail.Unwrap(err); fErr == nil {
+					t.Errorf("The returned error should be wrapped with fail.Error: %v", err)
+				} else if got, want := fErr.Code, int(c.code); got != want {
+					t.Errorf("The returned error has code %v, want %v", got, want)
+				}
+			} else {
+				if resp == nil {
+					t.Error("The request should return a response")
+				}
+
+				if err != nil {
+					t.Error("The request should not return any errors")
+				}
+			}
+
+			if got, want := handled, c.handled; got != want {
+				t.Errorf("The status handler is called: got %t, want %t", got, want)
+			}
+		})
+	}
+}
+
+func Test_UnaryClientInterceptor_WithInverseCodeMap(t *testing.T) {
+	m := CodeMap{50: codes.PermissionDenied, 51: codes.InvalidArgument, 52: codes.InvalidArgument}
+
+	cases := []struct {
+		test      string
+		server    errorstesting.TestServiceServer
+		detailFns []StatusDetailsFunc
+		failed    bool
+		code      interface{}
+		message   string
+		messages  []string
+		ignorable bool
+		tags      []string
+	}{
+		{
+			test:    "error with code that contained CodeMap",
+			server:  &errorWithStatusService{Code: 50},
+			failed:  true,
+			code:    50,
+			message: "This error has a status code",
+		},
+		{
+			test:   "error with code that cannot be restored uniquely",
+			server: &errorWithStatusService{Code: 51},
+		},
+		{
+			test:   "error with unknown code",
+			server: &errorWithStatusService{Code: 53},
+		},
+		{
+			test:      "error with FailDetail",
+			server:    &errorWithAnnotationsService{Code: 50},
+			detailFns: []StatusDetailsFunc{FailDetails(nil)},
+			failed:    true,
+			code:      50,
+			message:   "annotated: This error has annotations",
+			messages:  []string{"annotated"},
+			ignorable: true,
+			tags:      []string{"tag1", "tag2"},
+		},
+		{
+			test:      "error with FailDetail that has code that cannot be restored uniquely",
+			server:    &errorWithAnnotationsService{Code: 51},
+			detailFns: []StatusDetailsFunc{FailDetails(nil)},
+			failed:    true,
+			code:      "51",
+			message:   "annotated: This error has annotations",
+			messages:  []string{"annotated"},
+			ignorable: true,
+			tags:      []string{"tag1", "tag2"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			ctx := errorstesting.CreateTestContext(t)
+			ctx.Service = c.server
+			ctx.AddUnaryServerInterceptor(UnaryServerInterceptor(WithCodeMap(m, c.detailFns...)))
+			ctx.AddUnaryClientInterceptor(UnaryClientInterceptor(WithInverseCodeMap(m.Inverse())))
+			ctx.Setup()
+			defer ctx.Teardown()
+
+			_, err := ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+
+			if err == nil {
+				t.Fatal("The request should return an error")
+			}
+
+			fErr := fail.Unwrap(err)
+
+			if got, want := fErr != nil, c.failed; got != want {
+				t.Fatalf("The returned error is wrapped with fail.Error: got %t, want %t", got, want)
+			}
+
+			if fErr == nil {
+				return
+			}
+
+			if got, want := fErr.Code, c.code; got != want {
+				t.Errorf("The returned error has code %v, want %v", got, want)
+			}
+
+			if got, want := err.Error(), c.message; got != want {
+				t.Errorf("The returned error has message %q, want %q", got, want)
+			}
+
+			if got, want := fErr.Messages, c.messages; !reflect.DeepEqual(got, want) {
+				t.Errorf("The returned error has messages %v, want %v", got, want)
+			}
+
+			if got, want := fErr.Ignorable, c.ignorable; got != want {
+				t.Errorf("The returned error is ignorable: got %t, want %t", got, want)
+			}
+
+			if got, want := fErr.Tags, c.tags; !reflect.DeepEqual(got, want) {
+				t.Errorf("The returned error has tags %v, want %v", got, want)
+			}
+
+			if _, ok := status.FromError(fErr.Err); !ok {
+				t.Errorf("The returned error should wrap a gRPC status error: %v", fErr.Err)
+			}
+		})
+	}
+}
+
+func Test_StreamServerInterceptor(t *testing.T) {
+	cases := []struct {
+		test     string
+		call     func(context.Context, errorstesting.TestServiceClient) error
+		received int
+		sent     int
+	}{
+		{
+			test: "server streaming",
+			call: func(c context.Context, cli errorstesting.TestServiceClient) error {
+				stream, err := cli.ServerStreamCall(c, &errorstesting.Empty{})
+				if err != nil {
+					return err
+				}
+				for {
+					if _, err := stream.Recv(); err != nil {
+						return err
+					}
+				}
+			},
+			received: 1,
+			sent:     2,
+		},
+		{
+			test: "client streaming",
+			call: func(c context.Context, cli errorstesting.TestServiceClient) error {
+				stream, err := cli.ClientStreamCall(c)
+				if err != nil {
+					return err
+				}
+				for i := 0; i < 3; i++ {
+					if err := stream.Send(&errorstesting.Empty{}); err != nil {
+						return err
+					}
+				}
+				_, err = stream.CloseAndRecv()
+				return err
+			},
+			received: 3,
+			sent:     0,
+		},
+		{
+			test: "bidi streaming",
+			call: func(c context.Context, cli errorstesting.TestServiceClient) error {
+				stream, err := cli.BidiStreamCall(c)
+				if err != nil {
+					return err
+				}
+				for i := 0; i < 3; i++ {
+					if err := stream.Send(&errorstesting.Empty{}); err != nil {
+						return err
+					}
+					if _, err := stream.Recv(); err != nil {
+						return err
+					}
+				}
+				if err := stream.CloseSend(); err != nil {
+					return err
+				}
+				_, err = stream.Recv()
+				return err
+			},
+			received: 3,
+			sent:     3,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			var record *StreamRecord
+
+			ctx := errorstesting.CreateTestContext(t)
+			ctx.Service = &streamFailService{}
+			ctx.AddStreamServerInterceptor(
+				StreamServerInterceptor(
+					WithStreamRecorder(1),
+					WithReportableErrorHandler(func(c context.Context, err *fail.Error) error {
+						record, _ = StreamRecordFromContext(c)
+						return err
+					}),
+					WithCodeMap(CodeMap{50: codes.PermissionDenied}),
+				),
+			)
+			ctx.Setup()
+			defer ctx.Teardown()
+
+			err := c.call(context.Background(), ctx.Client)
+
+			if got, want := status.Code(err), codes.PermissionDenied; got != want {
+				t.Errorf("The returned error has error code %v, want %v", got, want)
+			}
+
+			if record == nil {
+				t.Fatal("Error handlers should receive a StreamRecord")
+			}
+
+			if got, want := record.Received.Count, c.received; got != want {
+				t.Errorf("The stream received %d messages, want %d", got, want)
+			}
+
+			if got, want := record.Sent.Count, c.sent; got != want {
+				t.Errorf("The stream sent %d messages, want %d", got, want)
+			}
+		})
+	}
+}
+
+func Test_StreamClientInterceptor_WithInverseCodeMap(t *testing.T) {
+	m := CodeMap{50: codes.PermissionDenied}
+
+	ctx := errorstesting.CreateTestContext(t)
+	ctx.Service = &streamFailService{}
+	ctx.AddStreamServerInterceptor(StreamServerInterceptor(WithCodeMap(m)))
+	ctx.AddStreamClientInterceptor(StreamClientInterceptor(WithInverseCodeMap(m.Inverse())))
+	ctx.Setup()
+	defer ctx.Teardown()
+
+	stream, err := ctx.Client.ServerStreamCall(context.Background(), &errorstesting.Empty{})
+	if err != nil {
+		t.Fatalf("The request should not return an error: %v", err)
+	}
+	for err == nil {
+		_, err = stream.Recv()
+	}
+
+	fErr := fail.Unwrap(err)
+	if fErr == nil {
+		t.Fatalf("The returned error should be wrapped with fail.Error: %v", err)
+	}
+
+	if got, want := fErr.Code, interface{}(50); got != want {
+		t.Errorf("The returned error has code %v, want %v", got, want)
+	}
+}
+
+type streamClientRecordingHandler struct {
+	mu       sync.Mutex
+	calls    int
+	request  interface{}
+	response interface{}
+}
+
+func (h *streamClientRecordingHandler) HandleStreamClientError(c context.Context, req, resp interface{}, desc *grpc.StreamDesc, method string, opts []grpc.CallOption, err error) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls++
+	h.request, h.response = req, resp
+	return err
+}
+
+func Test_StreamClientInterceptor_WithConcurrentSendAndRecv(t *testing.T) {
+	m := CodeMap{50: codes.PermissionDenied}
+	h := &streamClientRecordingHandler{}
+
+	ctx := errorstesting.CreateTestContext(t)
+	ctx.Service = &streamFailService{}
+	ctx.AddStreamServerInterceptor(StreamServerInterceptor(WithCodeMap(m)))
+	ctx.AddStreamClientInterceptor(StreamClientInterceptor(h, WithInverseCodeMap(m.Inverse())))
+	ctx.Setup()
+	defer ctx.Teardown()
+
+	stream, err := ctx.Client.BidiStreamCall(context.Background())
+	if err != nil {
+		t.Fatalf("The request should not return an error: %v", err)
+	}
+
+	sent := make(chan error, 1)
+	go func() {
+		for i := 0; i < 10; i++ {
+			if err := stream.Send(&errorstesting.Empty{}); err != nil {
+				sent <- err
+				return
+			}
+		}
+		sent <- stream.CloseSend()
+	}()
+
+	for err == nil {
+		_, err = stream.Recv()
+	}
+	if sErr := <-sent; sErr != nil {
+		t.Errorf("Sending messages should not fail: %v", sErr)
+	}
+
+	if got, want := fail.Unwrap(err), interface{}(50); got == nil || got.Code != want {
+		t.Errorf("The returned error should have code %v: %v", want, err)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	if h.calls == 0 || h.request == nil || h.response == nil {
+		t.Errorf("The handler should receive the last request and response: calls=%d, request=%v, response=%v", h.calls, h.request, h.response)
+	}
+}
+
+func Test_UnaryClientInterceptor_WithFailDetailRestorer(t *testing.T) {
+	m := CodeMap{51: codes.InvalidArgument, 52: codes.InvalidArgument}
+
+	ctx := errorstesting.CreateTestContext(t)
+	ctx.Service = &errorWithAnnotationsService{Code: 52}
+	ctx.AddUnaryServerInterceptor(
+		UnaryServerInterceptor(
+			WithCodeMap(m, FailDetails(func(context.Context) string { return "correlation-id" })),
+		),
+	)
+	ctx.AddUnaryClientInterceptor(UnaryClientInterceptor(WithFailDetailRestorer(m)))
+	ctx.Setup()
+	defer ctx.Teardown()
+
+	_, err := ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+
+	fErr := fail.Unwrap(err)
+	if fErr == nil {
+		t.Fatalf("The returned error should be wrapped with fail.Error: %v", err)
+	}
+
+	if got, want := fErr.Code, 52; got != want {
+		t.Errorf("The returned error has code %v, want %v", got, want)
+	}
+
+	if got, want := err.Error(), "annotated: This error has annotations"; got != want {
+		t.Errorf("The returned error has message %q, want %q", got, want)
+	}
+
+	if got, want := fErr.Messages, []string{"annotated"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("The returned error has messages %v, want %v", got, want)
+	}
+
+	if got, want := status.Code(fErr.Err), codes.InvalidArgument; got != want {
+		t.Errorf("The returned error wraps an error with code %v, want %v", got, want)
+	}
+
+	if !fErr.Ignorable {
+		t.Error("The returned error should be ignorable")
+	}
+
+	if got, want := fErr.Tags, []string{"tag1", "tag2"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("The returned error has tags %v, want %v", got, want)
+	}
+
+	if got, want := fErr.Params, (fail.H{"id": "1"}); !reflect.DeepEqual(got, want) {
+		t.Errorf("The returned error has params %v, want %v", got, want)
+	}
+}
+
+func Test_UnaryServerInterceptor_HandlerChain(t *testing.T) {
+	first := errorstesting.NewRecordingHandler("first")
+	second := errorstesting.NewRecordingHandler("second")
+	second.Func = func(context.Context, error) error { return nil }
+	third := errorstesting.NewRecordingHandler("third")
+
+	ctx := errorstesting.CreateTestContext(t)
+	ctx.Service = &errorService{}
+	ctx.AddUnaryServerInterceptor(UnaryServerInterceptor(first, second, third))
+	ctx.Setup()
+	defer ctx.Teardown()
+
+	for i := 0; i < 2; i++ {
+		ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+	}
+
+	if got, want := errorstesting.Order(first, second, third), []string{"first", "second", "first", "second"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Handlers are called in %v, want %v", got, want)
+	}
+
+	if third.Called() {
+		t.Error("Handlers after a handler returning nil should not be called")
+	}
+
+	if got, want := first.CountMethod("/errorstesting.TestService/EmptyCall"), 2; got != want {
+		t.Errorf("The handler is called %d times for EmptyCall, want %d", got, want)
+	}
+
+	call, _ := second.LastCall()
+	if got, want := call.Err.Error(), "This error is not wrapped with fail.Error"; got != want {
+		t.Errorf("The handler received error %q, want %q", got, want)
+	}
+
+	if call.ReturnedErr != nil {
+		t.Errorf("The handler returned error %v, want nil", call.ReturnedErr)
+	}
+}
+
+func Test_StreamServerInterceptor_HandlerChain(t *testing.T) {
+	first := errorstesting.NewRecordingHandler("first")
+	second := errorstesting.NewRecordingHandler("second")
+
+	ctx := errorstesting.CreateTestContext(t)
+	ctx.Service = &streamFailService{}
+	ctx.AddStreamServerInterceptor(StreamServerInterceptor(first, WithCodeMap(CodeMap{50: codes.PermissionDenied}), second))
+	ctx.Setup()
+	defer ctx.Teardown()
+
+	stream, err := ctx.Client.ServerStreamCall(context.Background(), &errorstesting.Empty{})
+	for err == nil {
+		_, err = stream.Recv()
+	}
+
+	if got, want := errorstesting.Order(first, second), []string{"first", "second"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("Handlers are called in %v, want %v", got, want)
+	}
+
+	call, ok := second.LastCall()
+	if !ok {
+		t.Fatal("The handler should be called")
+	}
+
+	if got, want := call.FullMethod, "/errorstesting.TestService/ServerStreamCall"; got != want {
+		t.Errorf("The handler is called for %q, want %q", got, want)
+	}
+
+	if got, want := status.Code(call.Err), codes.PermissionDenied; got != want {
+		t.Errorf("The handler received error code %v, want %v", got, want)
+	}
+
+	if call.Response == nil {
+		t.Error("The handler should receive the last sent message")
+	}
+}
@@ -0,0 +1,114 @@
+package grpcerrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/metadata"
+	"gopkg.in/yaml.v3"
+)
+
+// MessageCatalog is the interface that provides localized messages for status codes.
+type MessageCatalog interface {
+	Message(locale string, code interface{}) (string, bool)
+}
+
+// MapMessageCatalog is a MessageCatalog that maps locales to messages keyed by string representations of status codes.
+type MapMessageCatalog map[string]map[string]string
+
+// Message implements MessageCatalog.
+func (m MapMessageCatalog) Message(locale string, code interface{}) (string, bool) {
+	msg, ok := m[locale][fmt.Sprint(code)]
+	return msg, ok
+}
+
+// LoadMessageCatalogFile reads a MapMessageCatalog from a JSON or YAML file chosen by its extension.
+func LoadMessageCatalogFile(path string) (MapMessageCatalog, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var m MapMessageCatalog
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		err = json.Unmarshal(data, &m)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &m)
+	default:
+		return nil, fmt.Errorf("grpcerrors: unsupported message catalog format %q", ext)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("grpcerrors: failed to parse %s: %v", path, err)
+	}
+	return m, nil
+}
+
+// LocalizedMessageDetails returns a new StatusDetailsFunc that builds errdetails.LocalizedMessage from catalog.
+// A locale is negotiated with the "accept-language" header in incoming metadata,
+// and defaultLocale is used when no locales in the header are available.
+func LocalizedMessageDetails(catalog MessageCatalog, defaultLocale string) StatusDetailsFunc {
+	return func(c context.Context, err *fail.Error) []proto.Message {
+		var locales []string
+		if md, ok := metadata.FromIncomingContext(c); ok {
+			for _, v := range md.Get("accept-language") {
+				locales = append(locales, parseAcceptLanguage(v)...)
+			}
+		}
+		locales = append(locales, defaultLocale)
+
+		for _, locale := range locales {
+			if msg, ok := catalog.Message(locale, err.Code); ok {
+				return []proto.Message{&errdetails.LocalizedMessage{Locale: locale, Message: msg}}
+			}
+		}
+		return nil
+	}
+}
+
+// parseAcceptLanguage returns language tags in the order of preference.
+// A tag with a region like "ja-JP" is followed by its base language "ja".
+func parseAcceptLanguage(header string) []string {
+	type tag struct {
+		name string
+		q    float64
+	}
+	var tags []tag
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		name := strings.TrimSpace(fields[0])
+		if name == "" || name == "*" {
+			continue
+		}
+		q := 1.0
+		for _, f := range fields[1:] {
+			f = strings.TrimSpace(f)
+			if strings.HasPrefix(f, "q=") {
+				if v, err := strconv.ParseFloat(f[2:], 64); err == nil {
+					q = v
+				}
+			}
+		}
+		if q > 0 {
+			tags = append(tags, tag{name: name, q: q})
+		}
+	}
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].q > tags[j].q })
+
+	locales := make([]string, 0, len(tags))
+	for _, t := range tags {
+		locales = append(locales, t.name)
+		if i := strings.Index(t.name, "-"); i > 0 {
+			locales = append(locales, t.name[:i])
+		}
+	}
+	return locales
+}
@@ -0,0 +1,79 @@
+package grpcerrors
+
+import (
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+
+	"github.com/srvc/grpc-errors/testing"
+)
+
+func Test_UnaryServerInterceptor_WithLocalizedMessageDetails(t *testing.T) {
+	cases := []struct {
+		test           string
+		catalog        string
+		acceptLanguage string
+		locale         string
+		message        string
+	}{
+		{
+			test:           "YAML catalog with a preferred locale",
+			catalog:        "testdata/messages.yaml",
+			acceptLanguage: "fr;q=0.5, ja-JP, en;q=0.8",
+			locale:         "ja",
+			message:        "このリソースにアクセスする権限がありません。",
+		},
+		{
+			test:           "JSON catalog with an unavailable locale",
+			catalog:        "testdata/messages.json",
+			acceptLanguage: "fr",
+			locale:         "en",
+			message:        "You do not have permission to access this resource.",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			catalog, err := LoadMessageCatalogFile(c.catalog)
+			if err != nil {
+				t.Fatalf("Failed to load a catalog: %v", err)
+			}
+
+			ctx := errorstesting.CreateTestContext(t)
+			ctx.Service = &errorWithStatusService{Code: 50}
+			ctx.AddUnaryServerInterceptor(
+				UnaryServerInterceptor(
+					WithCodeMap(CodeMap{50: codes.PermissionDenied}, LocalizedMessageDetails(catalog, "en")),
+				),
+			)
+			ctx.Setup()
+			defer ctx.Teardown()
+
+			reqCtx := metadata.AppendToOutgoingContext(context.Background(), "accept-language", c.acceptLanguage)
+			_, err = ctx.Client.EmptyCall(reqCtx, &errorstesting.Empty{})
+
+			var localized *errdetails.LocalizedMessage
+			for _, d := range status.Convert(err).Details() {
+				if d, ok := d.(*errdetails.LocalizedMessage); ok {
+					localized = d
+				}
+			}
+
+			if localized == nil {
+				t.Fatal("The returned status should have LocalizedMessage")
+			}
+
+			if got, want := localized.Locale, c.locale; got != want {
+				t.Errorf("The returned LocalizedMessage has locale %q, want %q", got, want)
+			}
+
+			if got, want := localized.Message, c.message; got != want {
+				t.Errorf("The returned LocalizedMessage has message %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,127 @@
+package grpcerrors
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/status"
+)
+
+// LogLevel represents a severity of a logged error.
+type LogLevel int
+
+// Log levels of logged errors.
+const (
+	LogLevelWarn LogLevel = iota
+	LogLevelError
+)
+
+// String returns an upper-case name of the level, such as "WARN".
+func (l LogLevel) String() string {
+	switch l {
+	case LogLevelWarn:
+		return "WARN"
+	case LogLevelError:
+		return "ERROR"
+	}
+	return fmt.Sprintf("LogLevel(%d)", int(l))
+}
+
+// Log field keys that are passed to Logger.
+const (
+	LogFieldMethod     = "grpc.method"
+	LogFieldGrpcCode   = "grpc.code"
+	LogFieldCode       = "error.code"
+	LogFieldIgnorable  = "error.ignorable"
+	LogFieldTags       = "error.tags"
+	LogFieldParams     = "error.params"
+	LogFieldStackTrace = "error.stack_trace"
+)
+
+// Logger is the interface that writes handled errors to a logging backend.
+type Logger interface {
+	Log(c context.Context, level LogLevel, msg string, fields map[string]interface{})
+}
+
+// logLevel returns LogLevelWarn for errors annotated with the ignorability, and LogLevelError for the others.
+func logLevel(err error) LogLevel {
+	if fErr := fail.Unwrap(err); fErr != nil && fErr.Ignorable {
+		return LogLevelWarn
+	}
+	return LogLevelError
+}
+
+func logError(l Logger) ObserverFunc {
+	return func(c context.Context, method string, err, mapped error) {
+		level := logLevel(err)
+		fields := map[string]interface{}{
+			LogFieldMethod:   method,
+			LogFieldGrpcCode: status.Convert(mapped).Code().String(),
+		}
+		if fErr := fail.Unwrap(err); fErr != nil {
+			fields[LogFieldCode] = fErr.Code
+			fields[LogFieldIgnorable] = fErr.Ignorable
+			fields[LogFieldTags] = fErr.Tags
+			fields[LogFieldParams] = fErr.Params
+			fields[LogFieldStackTrace] = formatStackTrace(fErr.StackTrace)
+		}
+		l.Log(c, level, err.Error(), fields)
+	}
+}
+
+// WithLogger returns a new error handler that logs errors with l.
+// mapper is an error handler that maps errors to gRPC statuses, such as WithCodeMap,
+// and a logged gRPC code is taken from an error returned from it.
+// Errors are passed through without mapping when mapper is nil.
+// Errors annotated with the ignorability are logged as LogLevelWarn, and the others are logged as LogLevelError.
+func WithLogger(l Logger, mapper interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+}) interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+} {
+	return WithObserver(logError(l), mapper)
+}
+
+func formatStackTrace(st fail.StackTrace) []string {
+	lines := make([]string, 0, len(st))
+	for _, f := range st {
+		lines = append(lines, fmt.Sprintf("%s:%d %s", f.File, f.Line, f.Func))
+	}
+	return lines
+}
+
+type stdLogger struct {
+	l *log.Logger
+}
+
+// NewStdLogger returns a new Logger that writes errors with the standard library's logger.
+// Errors are written with the standard logger when l is nil.
+func NewStdLogger(l *log.Logger) Logger {
+	return &stdLogger{l: l}
+}
+
+func (l *stdLogger) Log(c context.Context, level LogLevel, msg string, fields map[string]interface{}) {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "[%s] %s", level, msg)
+	for _, k := range keys {
+		fmt.Fprintf(&b, " %s=%v", k, fields[k])
+	}
+
+	if l.l == nil {
+		log.Print(b.String())
+		return
+	}
+	l.l.Print(b.String())
+}
@@ -0,0 +1,42 @@
+//go:build go1.21
+// +build go1.21
+
+package grpcerrors
+
+import (
+	"log/slog"
+	"sort"
+
+	"golang.org/x/net/context"
+)
+
+type slogLogger struct {
+	l *slog.Logger
+}
+
+// NewSlogLogger returns a new Logger that writes errors with log/slog.
+// Errors are written with slog.Default() when l is nil.
+func NewSlogLogger(l *slog.Logger) Logger {
+	return &slogLogger{l: l}
+}
+
+func (l *slogLogger) Log(c context.Context, level LogLevel, msg string, fields map[string]interface{}) {
+	logger := l.l
+	if logger == nil {
+		logger = slog.Default()
+	}
+	slogLevel := slog.LevelError
+	if level == LogLevelWarn {
+		slogLevel = slog.LevelWarn
+	}
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	attrs := make([]slog.Attr, 0, len(fields))
+	for _, k := range keys {
+		attrs = append(attrs, slog.Any(k, fields[k]))
+	}
+	logger.LogAttrs(c, slogLevel, msg, attrs...)
+}
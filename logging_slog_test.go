@@ -0,0 +1,94 @@
+//go:build go1.21
+// +build go1.21
+
+package grpcerrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+
+	"github.com/srvc/grpc-errors/testing"
+)
+
+func Test_UnaryServerInterceptor_WithLogger_SlogLogger(t *testing.T) {
+	cases := []struct {
+		test   string
+		server errorstesting.TestServiceServer
+		level  string
+		msg    string
+		attrs  map[string]interface{}
+	}{
+		{
+			test:   "error with code that contained CodeMap",
+			server: &errorWithStatusService{Code: 50},
+			level:  "ERROR",
+			msg:    "This error has a status code",
+			attrs: map[string]interface{}{
+				LogFieldMethod:    "/errorstesting.TestService/EmptyCall",
+				LogFieldGrpcCode:  "PermissionDenied",
+				LogFieldCode:      float64(50),
+				LogFieldIgnorable: false,
+			},
+		},
+		{
+			test:   "ignored error",
+			server: &ignoredErrorService{},
+			level:  "WARN",
+			msg:    "This error should be ignored",
+			attrs: map[string]interface{}{
+				LogFieldGrpcCode:  "Unknown",
+				LogFieldIgnorable: true,
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			ctx := errorstesting.CreateTestContext(t)
+			ctx.Service = c.server
+			ctx.AddUnaryServerInterceptor(
+				UnaryServerInterceptor(
+					WithLogger(NewSlogLogger(slog.New(slog.NewJSONHandler(&buf, nil))), WithCodeMap(CodeMap{50: codes.PermissionDenied})),
+				),
+			)
+			ctx.Setup()
+			defer ctx.Teardown()
+
+			_, err := ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+
+			if err == nil {
+				t.Error("The request should return an error")
+			}
+
+			var record map[string]interface{}
+			if err := json.Unmarshal(buf.Bytes(), &record); err != nil {
+				t.Fatalf("Failed to decode the log %q: %v", buf.String(), err)
+			}
+
+			if got, want := record[slog.LevelKey], c.level; got != want {
+				t.Errorf("The log has level %v, want %v", got, want)
+			}
+
+			if got, want := record[slog.MessageKey], c.msg; got != want {
+				t.Errorf("The log has message %v, want %v", got, want)
+			}
+
+			for k, want := range c.attrs {
+				if got := record[k]; got != want {
+					t.Errorf("The log has attribute %s=%v, want %v", k, got, want)
+				}
+			}
+
+			if _, ok := record[LogFieldStackTrace].([]interface{}); !ok {
+				t.Errorf("The log should have a stack trace: %v", record[LogFieldStackTrace])
+			}
+		})
+	}
+}
@@ -0,0 +1,70 @@
+package grpcerrors
+
+import (
+	"bytes"
+	"log"
+	"strings"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+
+	"github.com/srvc/grpc-errors/testing"
+)
+
+func Test_UnaryServerInterceptor_WithLogger(t *testing.T) {
+	cases := []struct {
+		test     string
+		server   errorstesting.TestServiceServer
+		contains []string
+	}{
+		{
+			test:   "error with code that contained CodeMap",
+			server: &errorWithStatusService{Code: 50},
+			contains: []string{
+				"[ERROR] This error has a status code",
+				"grpc.method=/errorstesting.TestService/EmptyCall",
+				"grpc.code=PermissionDenied",
+				"error.code=50",
+				"error.ignorable=false",
+			},
+		},
+		{
+			test:   "ignored error",
+			server: &ignoredErrorService{},
+			contains: []string{
+				"[WARN] This error should be ignored",
+				"grpc.code=Unknown",
+				"error.ignorable=true",
+			},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			ctx := errorstesting.CreateTestContext(t)
+			ctx.Service = c.server
+			ctx.AddUnaryServerInterceptor(
+				UnaryServerInterceptor(
+					WithLogger(NewStdLogger(log.New(&buf, "", 0)), WithCodeMap(CodeMap{50: codes.PermissionDenied})),
+				),
+			)
+			ctx.Setup()
+			defer ctx.Teardown()
+
+			_, err := ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+
+			if err == nil {
+				t.Error("The request should return an error")
+			}
+
+			for _, want := range c.contains {
+				if !strings.Contains(buf.String(), want) {
+					t.Errorf("The log %q should contain %q", buf.String(), want)
+				}
+			}
+		})
+	}
+}
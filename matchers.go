@@ -0,0 +1,90 @@
+package grpcerrors
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+type errorIsHandler struct {
+	target error
+	f      ErrorHandlerFunc
+}
+
+func (h *errorIsHandler) HandleUnaryServerError(c context.Context, req interface{}, info *grpc.UnaryServerInfo, err error) error {
+	return h.handleError(c, err)
+}
+
+func (h *errorIsHandler) HandleStreamServerError(c context.Context, req interface{}, resp interface{}, info *grpc.StreamServerInfo, err error) error {
+	return h.handleError(c, err)
+}
+
+func (h *errorIsHandler) handleError(c context.Context, err error) error {
+	if errors.Is(err, h.target) {
+		return h.f(c, err)
+	}
+	return err
+}
+
+// WithErrorIs returns a new error handler function for handling errors that match target with errors.Is.
+func WithErrorIs(target error, f ErrorHandlerFunc) interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+} {
+	return &errorIsHandler{target: target, f: f}
+}
+
+var (
+	contextType = reflect.TypeOf((*context.Context)(nil)).Elem()
+	errorType   = reflect.TypeOf((*error)(nil)).Elem()
+)
+
+type errorAsHandler struct {
+	targetType reflect.Type
+	f          reflect.Value
+}
+
+func (h *errorAsHandler) HandleUnaryServerError(c context.Context, req interface{}, info *grpc.UnaryServerInfo, err error) error {
+	return h.handleError(c, err)
+}
+
+func (h *errorAsHandler) HandleStreamServerError(c context.Context, req interface{}, resp interface{}, info *grpc.StreamServerInfo, err error) error {
+	return h.handleError(c, err)
+}
+
+func (h *errorAsHandler) handleError(c context.Context, err error) error {
+	if err == nil {
+		return nil
+	}
+	target := reflect.New(h.targetType)
+	if !errors.As(err, target.Interface()) {
+		return err
+	}
+	out := h.f.Call([]reflect.Value{reflect.ValueOf(&c).Elem(), target.Elem()})
+	newErr, _ := out[0].Interface().(error)
+	return newErr
+}
+
+// WithErrorAs returns a new error handler function for handling errors that can be assigned with errors.As.
+// f should be a function like `func(context.Context, *MyError) error`,
+// and its second argument is a type implementing error or an interface type.
+// It panics if f does not have such a signature.
+func WithErrorAs(f interface{}) interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+} {
+	fv := reflect.ValueOf(f)
+	ft := fv.Type()
+	if ft.Kind() != reflect.Func || ft.NumIn() != 2 || ft.NumOut() != 1 ||
+		ft.In(0) != contextType || ft.Out(0) != errorType {
+		panic(fmt.Sprintf("grpcerrors: WithErrorAs requires func(context.Context, T) error, got %v", ft))
+	}
+	targetType := ft.In(1)
+	if targetType.Kind() != reflect.Interface && !targetType.Implements(errorType) {
+		panic(fmt.Sprintf("grpcerrors: WithErrorAs requires a type implementing error or an interface type, got %v", targetType))
+	}
+	return &errorAsHandler{targetType: targetType, f: fv}
+}
@@ -0,0 +1,99 @@
+package grpcerrors
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/srvc/grpc-errors/testing"
+)
+
+var errSentinel = errors.New("This is a sentinel error")
+
+type sentinelErrorService struct {
+	errorstesting.UnimplementedTestServiceServer
+}
+
+func (s *sentinelErrorService) EmptyCall(context.Context, *errorstesting.Empty) (*errorstesting.Empty, error) {
+	return nil, fmt.Errorf("wrapped with %%w: %w", errSentinel)
+}
+
+type customError struct {
+	Code codes.Code
+}
+
+func (e *customError) Error() string {
+	return "This error has a custom type"
+}
+
+type customErrorService struct {
+	errorstesting.UnimplementedTestServiceServer
+	Code codes.Code
+}
+
+func (s *customErrorService) EmptyCall(context.Context, *errorstesting.Empty) (*errorstesting.Empty, error) {
+	return nil, fail.Wrap(&customError{Code: s.Code})
+}
+
+func Test_UnaryServerInterceptor_WithErrorIsAndErrorAs(t *testing.T) {
+	cases := []struct {
+		test   string
+		server errorstesting.TestServiceServer
+		code   codes.Code
+	}{
+		{
+			test:   "error wrapping sentinel error",
+			server: &sentinelErrorService{},
+			code:   codes.NotFound,
+		},
+		{
+			test:   "error with custom type",
+			server: &customErrorService{Code: codes.FailedPrecondition},
+			code:   codes.FailedPrecondition,
+		},
+		{
+			test:   "error not matched",
+			server: &errorService{},
+			code:   codes.Unknown,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			ctx := errorstesting.CreateTestContext(t)
+			ctx.Service = c.server
+			ctx.AddUnaryServerInterceptor(
+				UnaryServerInterceptor(
+					WithErrorIs(errSentinel, func(_ context.Context, err error) error {
+						return status.Error(codes.NotFound, err.Error())
+					}),
+					WithErrorAs(func(_ context.Context, err *customError) error {
+						return status.Error(err.Code, err.Error())
+					}),
+				),
+			)
+			ctx.Setup()
+			defer ctx.Teardown()
+
+			_, err := ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+
+			if got, want := status.Code(err), c.code; got != want {
+				t.Errorf("The returned error has error code %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func Test_WithErrorAs_WhenAFunctionHasAnInvalidSignature(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("WithErrorAs should panic")
+		}
+	}()
+	WithErrorAs(func(_ context.Context, s string) error { return nil })
+}
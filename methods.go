@@ -0,0 +1,106 @@
+package grpcerrors
+
+import (
+	"fmt"
+	"path"
+	"strings"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+type methodScopedHandler struct {
+	h        interface{}
+	patterns []string
+	except   bool
+}
+
+func (h *methodScopedHandler) HandleUnaryServerError(c context.Context, req interface{}, info *grpc.UnaryServerInfo, err error) error {
+	if eh, ok := h.h.(UnaryServerErrorHandler); ok && h.match(info.FullMethod) {
+		return eh.HandleUnaryServerError(c, req, info, err)
+	}
+	return err
+}
+
+func (h *methodScopedHandler) HandleStreamServerError(c context.Context, req interface{}, resp interface{}, info *grpc.StreamServerInfo, err error) error {
+	if eh, ok := h.h.(StreamServerErrorHandler); ok && h.match(info.FullMethod) {
+		return eh.HandleStreamServerError(c, req, resp, info, err)
+	}
+	return err
+}
+
+func (h *methodScopedHandler) HandleUnaryClientError(c context.Context, method string, req, reply interface{}, opts []grpc.CallOption, err error) error {
+	if eh, ok := h.h.(UnaryClientErrorHandler); ok && h.match(method) {
+		return eh.HandleUnaryClientError(c, method, req, reply, opts, err)
+	}
+	return err
+}
+
+func (h *methodScopedHandler) HandleStreamClientError(c context.Context, req, resp interface{}, desc *grpc.StreamDesc, method string, opts []grpc.CallOption, err error) error {
+	if eh, ok := h.h.(StreamClientErrorHandler); ok && h.match(method) {
+		return eh.HandleStreamClientError(c, req, resp, desc, method, opts, err)
+	}
+	return err
+}
+
+func (h *methodScopedHandler) wrappedHandlers(fullMethod string) []interface{} {
+	if h.match(fullMethod) {
+		return []interface{}{h.h}
+	}
+	return nil
+}
+
+func (h *methodScopedHandler) match(fullMethod string) bool {
+	for _, p := range h.patterns {
+		if matchMethod(p, fullMethod) {
+			return !h.except
+		}
+	}
+	return h.except
+}
+
+func matchMethod(pattern, fullMethod string) bool {
+	if strings.HasSuffix(pattern, "*") && strings.HasPrefix(fullMethod, strings.TrimSuffix(pattern, "*")) {
+		return true
+	}
+	ok, _ := path.Match(pattern, fullMethod)
+	return ok
+}
+
+func newMethodScopedHandler(patterns []string, h interface{}, except bool) *methodScopedHandler {
+	switch h.(type) {
+	case UnaryServerErrorHandler, StreamServerErrorHandler, UnaryClientErrorHandler, StreamClientErrorHandler:
+	default:
+		panic(fmt.Sprintf("grpcerrors: %T does not implement any error handler interfaces", h))
+	}
+	for _, p := range patterns {
+		if _, err := path.Match(p, ""); err != nil {
+			panic(fmt.Sprintf("grpcerrors: invalid method pattern %q: %v", p, err))
+		}
+	}
+	return &methodScopedHandler{h: h, patterns: patterns, except: except}
+}
+
+// ForMethods returns a new error handler that calls h only for methods matched with any of patterns.
+// A pattern is matched with a full method name like "/package.Service/Method" by path.Match,
+// and a pattern ending with "*" also matches any full method names that have the preceding prefix.
+// h should implement at least one of error handler interfaces, otherwise it panics.
+func ForMethods(patterns []string, h interface{}) interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+	UnaryClientErrorHandler
+	StreamClientErrorHandler
+} {
+	return newMethodScopedHandler(patterns, h, false)
+}
+
+// ExceptMethods returns a new error handler that calls h only for methods not matched with any of patterns.
+// Patterns are matched in the same way as ForMethods.
+func ExceptMethods(patterns []string, h interface{}) interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+	UnaryClientErrorHandler
+	StreamClientErrorHandler
+} {
+	return newMethodScopedHandler(patterns, h, true)
+}
@@ -0,0 +1,81 @@
+package grpcerrors
+
+import (
+	"testing"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+
+	"github.com/srvc/grpc-errors/testing"
+)
+
+func Test_UnaryServerInterceptor_ForMethodsAndExceptMethods(t *testing.T) {
+	cases := []struct {
+		test       string
+		patterns   []string
+		except     bool
+		reportable bool
+	}{
+		{
+			test:       "exact method",
+			patterns:   []string{"/errorstesting.TestService/EmptyCall"},
+			reportable: true,
+		},
+		{
+			test:       "glob pattern",
+			patterns:   []string{"/errorstesting.*/*Call"},
+			reportable: true,
+		},
+		{
+			test:       "prefix pattern",
+			patterns:   []string{"/errorstesting.*"},
+			reportable: true,
+		},
+		{
+			test:     "not matched",
+			patterns: []string{"/grpc.health.v1.Health/*"},
+		},
+		{
+			test:     "except matched",
+			patterns: []string{"/errorstesting.TestService/*"},
+			except:   true,
+		},
+		{
+			test:       "except not matched",
+			patterns:   []string{"/grpc.health.v1.Health/*"},
+			except:     true,
+			reportable: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			var reportable bool
+
+			h := WithReportableErrorHandler(func(_ context.Context, err *fail.Error) error {
+				reportable = true
+				return err
+			})
+			scope := ForMethods
+			if c.except {
+				scope = ExceptMethods
+			}
+
+			ctx := errorstesting.CreateTestContext(t)
+			ctx.Service = &failService{}
+			ctx.AddUnaryServerInterceptor(UnaryServerInterceptor(scope(c.patterns, h)))
+			ctx.Setup()
+			defer ctx.Teardown()
+
+			_, err := ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+
+			if err == nil {
+				t.Error("The request should return an error")
+			}
+
+			if got, want := reportable, c.reportable; got != want {
+				t.Errorf("The scoped handler is called: got %t, want %t", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,107 @@
+package grpcerrors
+
+import (
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// ErrorLabelNames is label names of ErrorLabels in the same order as ErrorLabels.Values.
+// It can be used for a metric vector such as prometheus.CounterVec.
+var ErrorLabelNames = []string{"grpc_method", "code", "grpc_code", "reportable"}
+
+// ErrorLabels is a set of labels that classifies handled errors.
+type ErrorLabels struct {
+	// Method is a full method name.
+	Method string
+	// Code is a string representation of fail.Error's code. It is empty when an error has no code.
+	Code string
+	// GrpcCode is a gRPC's code of an error mapped to a gRPC status.
+	GrpcCode codes.Code
+	// Reportable represents whether an error is not annotated with the ignorability.
+	Reportable bool
+}
+
+// Values returns label values in the same order as ErrorLabelNames.
+func (l ErrorLabels) Values() []string {
+	return []string{l.Method, l.Code, l.GrpcCode.String(), strconv.FormatBool(l.Reportable)}
+}
+
+// ErrorCounter is the interface that counts handled errors.
+// The promgrpcerrors module provides an ErrorCounter that is also a prometheus.Collector.
+type ErrorCounter interface {
+	IncError(ErrorLabels)
+}
+
+func countError(counter ErrorCounter) ObserverFunc {
+	return func(c context.Context, method string, err, mapped error) {
+		labels := ErrorLabels{
+			Method:     method,
+			GrpcCode:   status.Convert(mapped).Code(),
+			Reportable: true,
+		}
+		if fErr := fail.Unwrap(err); fErr != nil {
+			if fErr.Code != nil {
+				labels.Code = fmt.Sprint(fErr.Code)
+			}
+			labels.Reportable = !fErr.Ignorable
+		}
+		counter.IncError(labels)
+	}
+}
+
+// WithErrorCounter returns a new error handler that counts errors with counter.
+// mapper is an error handler that maps errors to gRPC statuses, such as WithCodeMap,
+// and a counted gRPC code is taken from an error returned from it.
+// Errors are passed through without mapping when mapper is nil.
+func WithErrorCounter(counter ErrorCounter, mapper interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+}) interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+} {
+	return WithObserver(countError(counter), mapper)
+}
+
+// InMemoryErrorCounter is an ErrorCounter that keeps counts in memory.
+// It is safe for concurrent use.
+type InMemoryErrorCounter struct {
+	mu     sync.Mutex
+	counts map[ErrorLabels]int
+}
+
+// NewInMemoryErrorCounter returns a new InMemoryErrorCounter.
+func NewInMemoryErrorCounter() *InMemoryErrorCounter {
+	return &InMemoryErrorCounter{counts: make(map[ErrorLabels]int)}
+}
+
+// IncError implements ErrorCounter.
+func (c *InMemoryErrorCounter) IncError(labels ErrorLabels) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.counts[labels]++
+}
+
+// Count returns a count of errors with given labels.
+func (c *InMemoryErrorCounter) Count(labels ErrorLabels) int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.counts[labels]
+}
+
+// Counts returns a copy of all counts.
+func (c *InMemoryErrorCounter) Counts() map[ErrorLabels]int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	counts := make(map[ErrorLabels]int, len(c.counts))
+	for l, n := range c.counts {
+		counts[l] = n
+	}
+	return counts
+}
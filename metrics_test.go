@@ -0,0 +1,72 @@
+package grpcerrors
+
+import (
+	"reflect"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+
+	"github.com/srvc/grpc-errors/testing"
+)
+
+func Test_UnaryServerInterceptor_WithErrorCounter(t *testing.T) {
+	counter := NewInMemoryErrorCounter()
+	method := "/errorstesting.TestService/EmptyCall"
+
+	for _, svc := range []errorstesting.TestServiceServer{
+		&errorWithStatusService{Code: 50},
+		&errorWithStatusService{Code: 50},
+		&ignoredErrorService{},
+		&emptyService{},
+	} {
+		ctx := errorstesting.CreateTestContext(t)
+		ctx.Service = svc
+		ctx.AddUnaryServerInterceptor(
+			UnaryServerInterceptor(
+				WithErrorCounter(counter, WithCodeMap(CodeMap{50: codes.PermissionDenied})),
+			),
+		)
+		ctx.Setup()
+		ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+		ctx.Teardown()
+	}
+
+	want := map[ErrorLabels]int{
+		{Method: method, Code: "50", GrpcCode: codes.PermissionDenied, Reportable: true}: 2,
+		{Method: method, GrpcCode: codes.Unknown, Reportable: false}:                     1,
+	}
+
+	if got := counter.Counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Counted errors are %v, want %v", got, want)
+	}
+}
+
+func Test_StreamServerInterceptor_WithErrorCounter(t *testing.T) {
+	counter := NewInMemoryErrorCounter()
+
+	ctx := errorstesting.CreateTestContext(t)
+	ctx.Service = &streamFailService{}
+	ctx.AddStreamServerInterceptor(
+		StreamServerInterceptor(
+			WithErrorCounter(counter, WithCodeMap(CodeMap{50: codes.PermissionDenied})),
+		),
+	)
+	ctx.Setup()
+	defer ctx.Teardown()
+
+	for i := 0; i < 2; i++ {
+		stream, err := ctx.Client.ServerStreamCall(context.Background(), &errorstesting.Empty{})
+		for err == nil {
+			_, err = stream.Recv()
+		}
+	}
+
+	want := map[ErrorLabels]int{
+		{Method: "/errorstesting.TestService/ServerStreamCall", Code: "50", GrpcCode: codes.PermissionDenied, Reportable: true}: 2,
+	}
+
+	if got := counter.Counts(); !reflect.DeepEqual(got, want) {
+		t.Errorf("Counted errors are %v, want %v", got, want)
+	}
+}
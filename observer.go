@@ -0,0 +1,59 @@
+package grpcerrors
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// ObserverFunc is a function that observes an error and the error mapped to a gRPC status.
+type ObserverFunc func(c context.Context, method string, err, mapped error)
+
+type observingHandler struct {
+	mapper interface {
+		UnaryServerErrorHandler
+		StreamServerErrorHandler
+	}
+	observe ObserverFunc
+}
+
+func (h *observingHandler) HandleUnaryServerError(c context.Context, req interface{}, info *grpc.UnaryServerInfo, err error) error {
+	mapped := err
+	if h.mapper != nil {
+		mapped = h.mapper.HandleUnaryServerError(c, req, info, err)
+	}
+	if err != nil {
+		h.observe(c, info.FullMethod, err, mapped)
+	}
+	return mapped
+}
+
+func (h *observingHandler) HandleStreamServerError(c context.Context, req interface{}, resp interface{}, info *grpc.StreamServerInfo, err error) error {
+	mapped := err
+	if h.mapper != nil {
+		mapped = h.mapper.HandleStreamServerError(c, req, resp, info, err)
+	}
+	if err != nil {
+		h.observe(c, info.FullMethod, err, mapped)
+	}
+	return mapped
+}
+
+func (h *observingHandler) wrappedHandlers(fullMethod string) []interface{} {
+	if h.mapper == nil {
+		return nil
+	}
+	return []interface{}{h.mapper}
+}
+
+// WithObserver returns a new error handler that calls f with errors and errors mapped by mapper.
+// mapper is an error handler that maps errors to gRPC statuses, such as WithCodeMap.
+// Errors are passed through without mapping when mapper is nil.
+func WithObserver(f ObserverFunc, mapper interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+}) interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+} {
+	return &observingHandler{mapper: mapper, observe: f}
+}
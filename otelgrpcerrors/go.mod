@@ -0,0 +1,30 @@
+module github.com/srvc/grpc-errors/otelgrpcerrors
+
+go 1.21
+
+require (
+	github.com/srvc/fail/v4 v4.1.1
+	github.com/srvc/grpc-errors v1.3.0
+	go.opentelemetry.io/otel v1.28.0
+	go.opentelemetry.io/otel/sdk v1.28.0
+	go.opentelemetry.io/otel/trace v1.28.0
+	google.golang.org/grpc v1.14.0
+)
+
+require (
+	github.com/go-logr/logr v1.4.2 // indirect
+	github.com/go-logr/stdr v1.2.2 // indirect
+	github.com/golang/protobuf v1.1.0 // indirect
+	github.com/google/uuid v1.6.0 // indirect
+	github.com/pelletier/go-toml v1.9.5 // indirect
+	github.com/pkg/errors v0.8.1 // indirect
+	go.opentelemetry.io/otel/metric v1.28.0 // indirect
+	golang.org/x/net v0.0.0-20180816102801-aaf60122140d // indirect
+	golang.org/x/sys v0.21.0 // indirect
+	golang.org/x/text v0.3.0 // indirect
+	google.golang.org/genproto v0.0.0-20180817151627-c66870c02cf8 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The sibling checkout is used for developing both modules together. Consumers get the required release.
+replace github.com/srvc/grpc-errors => ../
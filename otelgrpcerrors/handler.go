@@ -0,0 +1,78 @@
+// Package otelgrpcerrors provides error handlers that annotate OpenTelemetry spans with handled errors.
+//
+// Its own go.mod keeps OpenTelemetry out of the dependency graph of github.com/srvc/grpc-errors.
+package otelgrpcerrors
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/srvc/fail/v4"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	grpcerrors "github.com/srvc/grpc-errors"
+)
+
+// Attribute keys that are set on recorded error events.
+const (
+	CodeKey       = attribute.Key("error.code")
+	IgnorableKey  = attribute.Key("error.ignorable")
+	TagsKey       = attribute.Key("error.tags")
+	StackTraceKey = attribute.Key("exception.stacktrace")
+	GrpcCodeKey   = attribute.Key("rpc.grpc.status_code")
+)
+
+// WithSpanAnnotator returns a new error handler that records errors to the active span in a context.
+// mapper is an error handler that maps errors to gRPC statuses, such as grpcerrors.WithCodeMap,
+// and the span status is set from the gRPC code of an error returned from it.
+// Errors annotated with the ignorability are recorded as events, but they do not set the span status to an error.
+func WithSpanAnnotator(mapper interface {
+	grpcerrors.UnaryServerErrorHandler
+	grpcerrors.StreamServerErrorHandler
+}) interface {
+	grpcerrors.UnaryServerErrorHandler
+	grpcerrors.StreamServerErrorHandler
+} {
+	return grpcerrors.WithObserver(annotateSpan, mapper)
+}
+
+func annotateSpan(c context.Context, method string, err, mapped error) {
+	span := trace.SpanFromContext(c)
+	if !span.IsRecording() {
+		return
+	}
+
+	code := status.Convert(mapped).Code()
+	attrs := []attribute.KeyValue{GrpcCodeKey.Int64(int64(code))}
+	ignorable := false
+	if fErr := fail.Unwrap(err); fErr != nil {
+		ignorable = fErr.Ignorable
+		if fErr.Code != nil {
+			attrs = append(attrs, CodeKey.String(fmt.Sprint(fErr.Code)))
+		}
+		attrs = append(attrs,
+			IgnorableKey.Bool(fErr.Ignorable),
+			TagsKey.StringSlice(fErr.Tags),
+			StackTraceKey.String(formatStackTrace(fErr.StackTrace)),
+		)
+	}
+
+	span.RecordError(err, trace.WithAttributes(attrs...))
+	span.SetAttributes(GrpcCodeKey.Int64(int64(code)))
+	if code != codes.OK && !ignorable {
+		span.SetStatus(otelcodes.Error, status.Convert(mapped).Message())
+	}
+}
+
+func formatStackTrace(st fail.StackTrace) string {
+	lines := make([]string, 0, len(st))
+	for _, f := range st {
+		lines = append(lines, fmt.Sprintf("%s\n\t%s:%d", f.Func, f.File, f.Line))
+	}
+	return strings.Join(lines, "\n")
+}
@@ -0,0 +1,76 @@
+package otelgrpcerrors
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/srvc/fail/v4"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	grpcerrors "github.com/srvc/grpc-errors"
+)
+
+func Test_WithSpanAnnotator(t *testing.T) {
+	cases := []struct {
+		test     string
+		err      error
+		status   otelcodes.Code
+		failCode string
+	}{
+		{
+			test:     "error with code that contained CodeMap",
+			err:      fail.Wrap(errors.New("This error has a status code"), fail.WithCode(50), fail.WithTags("tag")),
+			status:   otelcodes.Error,
+			failCode: "50",
+		},
+		{
+			test:   "ignored error",
+			err:    fail.Wrap(errors.New("This error should be ignored"), fail.WithIgnorable()),
+			status: otelcodes.Unset,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			ctx, span := tp.Tracer("test").Start(context.Background(), "EmptyCall")
+
+			h := WithSpanAnnotator(grpcerrors.WithCodeMap(grpcerrors.CodeMap{50: codes.PermissionDenied}))
+			err := h.HandleUnaryServerError(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/errorstesting.TestService/EmptyCall"}, c.err)
+			span.End()
+
+			if err == nil {
+				t.Error("The handler should return an error")
+			}
+
+			spans := exporter.GetSpans()
+			if got, want := len(spans), 1; got != want {
+				t.Fatalf("Exported %d spans, want %d", got, want)
+			}
+
+			if got, want := spans[0].Status.Code, c.status; got != want {
+				t.Errorf("The span has status %v, want %v", got, want)
+			}
+
+			if got, want := len(spans[0].Events), 1; got != want {
+				t.Fatalf("The span has %d events, want %d", got, want)
+			}
+
+			var failCode string
+			for _, attr := range spans[0].Events[0].Attributes {
+				if attr.Key == CodeKey {
+					failCode = attr.Value.AsString()
+				}
+			}
+			if got, want := failCode, c.failCode; got != want {
+				t.Errorf("The recorded event has fail code %q, want %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,172 @@
+package otelgrpcerrors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/srvc/fail/v4"
+	"go.opentelemetry.io/otel/attribute"
+	otelcodes "go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	grpcerrors "github.com/srvc/grpc-errors"
+	"github.com/srvc/grpc-errors/testing"
+	"github.com/srvc/grpc-errors/testing/assert"
+)
+
+type failService struct {
+	errorstesting.UnimplementedTestServiceServer
+	err error
+}
+
+func (s *failService) EmptyCall(context.Context, *errorstesting.Empty) (*errorstesting.Empty, error) {
+	return nil, s.err
+}
+
+func (s *failService) ServerStreamCall(_ *errorstesting.Empty, stream errorstesting.TestService_ServerStreamCallServer) error {
+	if err := stream.Send(&errorstesting.Empty{}); err != nil {
+		return err
+	}
+	return s.err
+}
+
+type tracedServerStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *tracedServerStream) Context() context.Context {
+	return s.ctx
+}
+
+// addTracingInterceptors adds interceptors that start a span for each call, as instrumentation libraries do.
+func addTracingInterceptors(ctx *errorstesting.TestContext, tracer trace.Tracer) {
+	ctx.AddUnaryServerInterceptor(func(c context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		c, span := tracer.Start(c, info.FullMethod)
+		defer span.End()
+		return handler(c, req)
+	})
+	ctx.AddStreamServerInterceptor(func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		c, span := tracer.Start(ss.Context(), info.FullMethod)
+		defer span.End()
+		return handler(srv, &tracedServerStream{ServerStream: ss, ctx: c})
+	})
+}
+
+func Test_Interceptors_WithSpanAnnotator(t *testing.T) {
+	cases := []struct {
+		test     string
+		call     func(context.Context, errorstesting.TestServiceClient) error
+		err      error
+		code     codes.Code
+		status   otelcodes.Code
+		failCode string
+		tags     []string
+	}{
+		{
+			test: "unary call with an error with code that contained CodeMap",
+			call: func(c context.Context, cli errorstesting.TestServiceClient) error {
+				_, err := cli.EmptyCall(c, &errorstesting.Empty{})
+				return err
+			},
+			err:      fail.Wrap(errors.New("This error has a status code"), fail.WithCode(50), fail.WithTags("tag")),
+			code:     codes.PermissionDenied,
+			status:   otelcodes.Error,
+			failCode: "50",
+			tags:     []string{"tag"},
+		},
+		{
+			test: "unary call with an ignored error",
+			call: func(c context.Context, cli errorstesting.TestServiceClient) error {
+				_, err := cli.EmptyCall(c, &errorstesting.Empty{})
+				return err
+			},
+			err:    fail.Wrap(errors.New("This error should be ignored"), fail.WithIgnorable()),
+			code:   codes.Unknown,
+			status: otelcodes.Unset,
+		},
+		{
+			test: "server streaming with an error with code that contained CodeMap",
+			call: func(c context.Context, cli errorstesting.TestServiceClient) error {
+				stream, err := cli.ServerStreamCall(c, &errorstesting.Empty{})
+				if err != nil {
+					return err
+				}
+				for {
+					if _, err := stream.Recv(); err != nil {
+						return err
+					}
+				}
+			},
+			err:      fail.Wrap(errors.New("This stream always fails"), fail.WithCode(50), fail.WithTags("tag")),
+			code:     codes.PermissionDenied,
+			status:   otelcodes.Error,
+			failCode: "50",
+			tags:     []string{"tag"},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			exporter := tracetest.NewInMemoryExporter()
+			tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+			h := WithSpanAnnotator(grpcerrors.WithCodeMap(grpcerrors.CodeMap{50: codes.PermissionDenied}))
+
+			ctx := errorstesting.CreateTestContext(t)
+			ctx.Service = &failService{err: c.err}
+			addTracingInterceptors(ctx, tp.Tracer("test"))
+			ctx.AddUnaryServerInterceptor(grpcerrors.UnaryServerInterceptor(h))
+			ctx.AddStreamServerInterceptor(grpcerrors.StreamServerInterceptor(h))
+			ctx.Setup()
+			defer ctx.Teardown()
+
+			err := c.call(context.Background(), ctx.Client)
+
+			assert.Code(t, err, c.code)
+
+			spans := exporter.GetSpans()
+			if got, want := len(spans), 1; got != want {
+				t.Fatalf("Exported %d spans, want %d", got, want)
+			}
+
+			if got, want := spans[0].Status.Code, c.status; got != want {
+				t.Errorf("The span has status %v, want %v", got, want)
+			}
+
+			var grpcCode int64
+			for _, attr := range spans[0].Attributes {
+				if attr.Key == GrpcCodeKey {
+					grpcCode = attr.Value.AsInt64()
+				}
+			}
+			if got, want := grpcCode, int64(c.code); got != want {
+				t.Errorf("The span has gRPC code %d, want %d", got, want)
+			}
+
+			if got, want := len(spans[0].Events), 1; got != want {
+				t.Fatalf("The span has %d events, want %d", got, want)
+			}
+
+			attrs := attribute.NewSet(spans[0].Events[0].Attributes...)
+			var failCode string
+			if v, ok := attrs.Value(CodeKey); ok {
+				failCode = v.AsString()
+			}
+			if got, want := failCode, c.failCode; got != want {
+				t.Errorf("The recorded event has fail code %q, want %q", got, want)
+			}
+			if v, ok := attrs.Value(IgnorableKey); !ok || v.AsBool() != (c.status == otelcodes.Unset) {
+				t.Errorf("The recorded event has ignorability %v, want %v", v.AsBool(), c.status == otelcodes.Unset)
+			}
+			if v, _ := attrs.Value(TagsKey); strings.Join(v.AsStringSlice(), ",") != strings.Join(c.tags, ",") {
+				t.Errorf("The recorded event has tags %v, want %v", v.AsStringSlice(), c.tags)
+			}
+		})
+	}
+}
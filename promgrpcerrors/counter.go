@@ -0,0 +1,48 @@
+// Package promgrpcerrors provides a Prometheus collector that counts errors handled by grpcerrors.WithErrorCounter.
+//
+// It is versioned apart from github.com/srvc/grpc-errors, so that applications without Prometheus do not depend on its client.
+package promgrpcerrors
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	grpcerrors "github.com/srvc/grpc-errors"
+)
+
+// ErrorCounter is a grpcerrors.ErrorCounter and a prometheus.Collector.
+// It counts errors with a counter vector labeled with grpcerrors.ErrorLabelNames.
+type ErrorCounter struct {
+	vec *prometheus.CounterVec
+}
+
+var (
+	_ grpcerrors.ErrorCounter = (*ErrorCounter)(nil)
+	_ prometheus.Collector    = (*ErrorCounter)(nil)
+)
+
+// NewErrorCounter returns a new ErrorCounter. Labels are given by grpcerrors.ErrorLabelNames.
+// The counter is named "grpc_server_handled_errors_total" when opts.Name is empty.
+func NewErrorCounter(opts prometheus.CounterOpts) *ErrorCounter {
+	if opts.Name == "" {
+		opts.Name = "grpc_server_handled_errors_total"
+	}
+	if opts.Help == "" {
+		opts.Help = "Total number of errors handled on gRPC servers."
+	}
+	return &ErrorCounter{vec: prometheus.NewCounterVec(opts, grpcerrors.ErrorLabelNames)}
+}
+
+// IncError implements grpcerrors.ErrorCounter.
+func (c *ErrorCounter) IncError(labels grpcerrors.ErrorLabels) {
+	c.vec.WithLabelValues(labels.Values()...).Inc()
+}
+
+// Describe implements prometheus.Collector.
+func (c *ErrorCounter) Describe(ch chan<- *prometheus.Desc) {
+	c.vec.Describe(ch)
+}
+
+// Collect implements prometheus.Collector.
+func (c *ErrorCounter) Collect(ch chan<- prometheus.Metric) {
+	c.vec.Collect(ch)
+}
@@ -0,0 +1,39 @@
+package promgrpcerrors
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+
+	grpcerrors "github.com/srvc/grpc-errors"
+)
+
+func Test_ErrorCounter(t *testing.T) {
+	counter := NewErrorCounter(prometheus.CounterOpts{})
+	registry := prometheus.NewPedanticRegistry()
+	if err := registry.Register(counter); err != nil {
+		t.Fatalf("Failed to register the counter: %v", err)
+	}
+
+	h := grpcerrors.WithErrorCounter(counter, grpcerrors.WithCodeMap(grpcerrors.CodeMap{50: codes.PermissionDenied}))
+	info := &grpc.UnaryServerInfo{FullMethod: "/errorstesting.TestService/EmptyCall"}
+	for i := 0; i < 2; i++ {
+		h.HandleUnaryServerError(context.Background(), nil, info, fail.Wrap(errors.New("error"), fail.WithCode(50)))
+	}
+
+	want := `
+# HELP grpc_server_handled_errors_total Total number of errors handled on gRPC servers.
+# TYPE grpc_server_handled_errors_total counter
+grpc_server_handled_errors_total{code="50",grpc_code="PermissionDenied",grpc_method="/errorstesting.TestService/EmptyCall",reportable="true"} 2
+`
+	if err := testutil.GatherAndCompare(registry, strings.NewReader(want)); err != nil {
+		t.Errorf("Gathered metrics are unexpected: %v", err)
+	}
+}
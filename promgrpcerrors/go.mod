@@ -0,0 +1,31 @@
+module github.com/srvc/grpc-errors/promgrpcerrors
+
+go 1.20
+
+require (
+	github.com/prometheus/client_golang v1.19.1
+	github.com/srvc/fail/v4 v4.1.1
+	github.com/srvc/grpc-errors v1.3.0
+	golang.org/x/net v0.20.0
+	google.golang.org/grpc v1.14.0
+)
+
+require (
+	github.com/beorn7/perks v1.0.1 // indirect
+	github.com/cespare/xxhash/v2 v2.2.0 // indirect
+	github.com/davecgh/go-spew v1.1.1 // indirect
+	github.com/golang/protobuf v1.5.4 // indirect
+	github.com/pelletier/go-toml v1.9.5 // indirect
+	github.com/pkg/errors v0.8.1 // indirect
+	github.com/prometheus/client_model v0.5.0 // indirect
+	github.com/prometheus/common v0.48.0 // indirect
+	github.com/prometheus/procfs v0.12.0 // indirect
+	golang.org/x/sys v0.17.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto v0.0.0-20180817151627-c66870c02cf8 // indirect
+	google.golang.org/protobuf v1.33.0 // indirect
+	gopkg.in/yaml.v3 v3.0.1 // indirect
+)
+
+// The sibling checkout is used for developing both modules together. Consumers get the required release.
+replace github.com/srvc/grpc-errors => ../
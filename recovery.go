@@ -0,0 +1,68 @@
+package grpcerrors
+
+import (
+	"fmt"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PanicError is an error recovered from a panic in a service method.
+type PanicError struct {
+	Value interface{}
+}
+
+// Error returns a message with the panic value.
+func (e *PanicError) Error() string {
+	return fmt.Sprintf("panic: %v", e.Value)
+}
+
+// panicStatusMessage is a message of statuses mapped from recovered panics.
+// Panic values are not sent to clients since they may contain internal details.
+const panicStatusMessage = "internal error"
+
+type panicRecoveryHandler struct{}
+
+func (h *panicRecoveryHandler) HandleUnaryServerError(c context.Context, req interface{}, info *grpc.UnaryServerInfo, err error) error {
+	return h.handleError(err)
+}
+
+func (h *panicRecoveryHandler) HandleStreamServerError(c context.Context, req interface{}, resp interface{}, info *grpc.StreamServerInfo, err error) error {
+	return h.handleError(err)
+}
+
+func (h *panicRecoveryHandler) handleError(err error) error {
+	if fErr := fail.Unwrap(err); fErr != nil {
+		if _, ok := fErr.Err.(*PanicError); ok {
+			return status.Error(codes.Internal, panicStatusMessage)
+		}
+	}
+	return err
+}
+
+// WithPanicRecovery returns a new error handler that makes interceptors recover panics in service methods.
+// A recovered panic is wrapped with fail.Error as PanicError, and it is passed through the error handler chain.
+// The handler maps recovered panics that have not been converted by preceding handlers to codes.Internal with a generic message.
+// Preceding handlers receive PanicError with a panic value. Put it before mappers that convert any errors,
+// such as WithCodeMapper, since they would send the panic value to clients; reporters and loggers can precede it.
+// It also takes effect when it is wrapped by other handlers such as ForMethods.
+func WithPanicRecovery() interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+} {
+	return &panicRecoveryHandler{}
+}
+
+func newPanicError(r interface{}) error {
+	return fail.Wrap(&PanicError{Value: r})
+}
+
+func hasPanicRecovery(handlers []interface{}, fullMethod string) bool {
+	return findHandler(handlers, fullMethod, func(h interface{}) bool {
+		_, ok := h.(*panicRecoveryHandler)
+		return ok
+	}) != nil
+}
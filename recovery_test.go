@@ -0,0 +1,143 @@
+package grpcerrors
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/srvc/grpc-errors/testing"
+)
+
+type streamPanicService struct {
+	errorstesting.UnimplementedTestServiceServer
+}
+
+func (s *streamPanicService) ServerStreamCall(*errorstesting.Empty, errorstesting.TestService_ServerStreamCallServer) error {
+	panic("This service always panics: password=secret")
+}
+
+func Test_UnaryServerInterceptor_WithPanicRecovery(t *testing.T) {
+	cases := []struct {
+		test    string
+		handler UnaryServerErrorHandler
+	}{
+		{
+			test:    "WithPanicRecovery",
+			handler: WithPanicRecovery(),
+		},
+		{
+			test:    "WithPanicRecovery wrapped by ForMethods",
+			handler: ForMethods([]string{"/errorstesting.TestService/EmptyCall"}, WithPanicRecovery()),
+		},
+		{
+			test:    "WithPanicRecovery wrapped by WithObserver",
+			handler: WithObserver(func(context.Context, string, error, error) {}, WithPanicRecovery()),
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			var reported *fail.Error
+
+			ctx := errorstesting.CreateTestContext(t)
+			ctx.Service = &panicService{}
+			ctx.AddUnaryServerInterceptor(
+				UnaryServerInterceptor(
+					WithReportableErrorHandler(func(_ context.Context, err *fail.Error) error {
+						reported = err
+						return err
+					}),
+					c.handler,
+				),
+			)
+			ctx.Setup()
+			defer ctx.Teardown()
+
+			_, err := ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+
+			if got, want := status.Code(err), codes.Internal; got != want {
+				t.Errorf("The returned error has error code %v, want %v", got, want)
+			}
+
+			if msg := status.Convert(err).Message(); strings.Contains(msg, "This service always panics") {
+				t.Errorf("The returned error should not contain the panic value: %q", msg)
+			}
+
+			if reported == nil {
+				t.Fatal("The recovered panic should be reported")
+			}
+
+			if pErr, ok := reported.Err.(*PanicError); !ok {
+				t.Errorf("The reported error should be PanicError: %v", reported.Err)
+			} else if got, want := pErr.Value, "This service always panics"; got != want {
+				t.Errorf("The reported error has panic value %v, want %v", got, want)
+			}
+
+			if len(reported.StackTrace) == 0 {
+				t.Error("The reported error should have a stack trace")
+			}
+		})
+	}
+}
+
+func Test_StreamServerInterceptor_WithPanicRecovery(t *testing.T) {
+	var reported *fail.Error
+
+	ctx := errorstesting.CreateTestContext(t)
+	ctx.Service = &streamPanicService{}
+	ctx.AddStreamServerInterceptor(
+		StreamServerInterceptor(
+			WithReportableErrorHandler(func(_ context.Context, err *fail.Error) error {
+				reported = err
+				return err
+			}),
+			WithPanicRecovery(),
+		),
+	)
+	ctx.Setup()
+	defer ctx.Teardown()
+
+	stream, err := ctx.Client.ServerStreamCall(context.Background(), &errorstesting.Empty{})
+	for err == nil {
+		_, err = stream.Recv()
+	}
+
+	if got, want := status.Code(err), codes.Internal; got != want {
+		t.Errorf("The returned error has error code %v, want %v", got, want)
+	}
+
+	if msg := status.Convert(err).Message(); strings.Contains(msg, "password") {
+		t.Errorf("The returned error should not contain the panic value: %q", msg)
+	}
+
+	if reported == nil {
+		t.Fatal("The recovered panic should be reported")
+	}
+
+	if _, ok := reported.Err.(*PanicError); !ok {
+		t.Errorf("The reported error should be PanicError: %v", reported.Err)
+	}
+}
+
+func Test_methodFeaturesCache(t *testing.T) {
+	cache := newMethodFeaturesCache(unaryServerHandlers([]UnaryServerErrorHandler{
+		ForMethods([]string{"/errorstesting.TestService/*"}, WithPanicRecovery()),
+	}))
+
+	f := cache.get("/errorstesting.TestService/EmptyCall")
+	if !f.recovery {
+		t.Error("Panics should be recovered in matched methods")
+	}
+
+	if cache.get("/errorstesting.TestService/EmptyCall") != f {
+		t.Error("Features should be cached by full methods")
+	}
+
+	if cache.get("/other.Service/EmptyCall").recovery {
+		t.Error("Panics should not be recovered in other methods")
+	}
+}
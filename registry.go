@@ -0,0 +1,234 @@
+package grpcerrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"text/template"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+)
+
+// ErrorDefinition describes an application error registered to a Registry.
+type ErrorDefinition struct {
+	// Code is a status code of the error.
+	Code interface{}
+	// GrpcCode is a gRPC code that Code is mapped to.
+	GrpcCode codes.Code
+	// Description describes when the error occurs.
+	Description string
+	// Retryable represents whether clients can retry requests failed with the error.
+	Retryable bool
+	// PublicMessage is a text/template for messages sent to clients. It is executed with fail.Error's params.
+	// When it is empty or the template fails, for example when a param used in it is missing,
+	// Description, or the name of GrpcCode when Description is also empty, is sent instead.
+	// Messages of errors are never sent to clients.
+	PublicMessage string
+}
+
+type registeredError struct {
+	def  ErrorDefinition
+	tmpl *template.Template
+}
+
+// Registry is a catalog of application errors. It is safe for concurrent use.
+type Registry struct {
+	mu     sync.RWMutex
+	errs   []*registeredError
+	byCode map[interface{}]*registeredError
+}
+
+// NewRegistry returns a new empty Registry.
+func NewRegistry() *Registry {
+	return &Registry{byCode: make(map[interface{}]*registeredError)}
+}
+
+// Register adds error definitions to the registry.
+// It returns an error when a code is already registered or given more than once, or a public message template is invalid.
+// No definitions are added when it returns an error.
+func (r *Registry) Register(defs ...ErrorDefinition) error {
+	errs := make([]*registeredError, 0, len(defs))
+	seen := make(map[interface{}]bool, len(defs))
+	for _, def := range defs {
+		if seen[def.Code] {
+			return fmt.Errorf("grpcerrors: code %v is given more than once", def.Code)
+		}
+		seen[def.Code] = true
+		e := &registeredError{def: def}
+		if def.PublicMessage != "" {
+			tmpl, err := template.New(fmt.Sprint(def.Code)).Option("missingkey=error").Parse(def.PublicMessage)
+			if err != nil {
+				return fmt.Errorf("grpcerrors: invalid public message of code %v: %v", def.Code, err)
+			}
+			e.tmpl = tmpl
+		}
+		errs = append(errs, e)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, e := range errs {
+		if _, ok := r.byCode[e.def.Code]; ok {
+			return fmt.Errorf("grpcerrors: code %v is already registered", e.def.Code)
+		}
+	}
+	for _, e := range errs {
+		r.errs = append(r.errs, e)
+		r.byCode[e.def.Code] = e
+	}
+	return nil
+}
+
+// MustRegister is like Register but panics if an error occurs.
+func (r *Registry) MustRegister(defs ...ErrorDefinition) {
+	if err := r.Register(defs...); err != nil {
+		panic(err)
+	}
+}
+
+// Lookup returns an error definition of a code.
+func (r *Registry) Lookup(code interface{}) (ErrorDefinition, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	e, ok := r.byCode[code]
+	if !ok {
+		return ErrorDefinition{}, false
+	}
+	return e.def, true
+}
+
+// Definitions returns all error definitions in registration order.
+func (r *Registry) Definitions() []ErrorDefinition {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	defs := make([]ErrorDefinition, 0, len(r.errs))
+	for _, e := range r.errs {
+		defs = append(defs, e.def)
+	}
+	return defs
+}
+
+// CodeMap returns a CodeMap built from registered errors.
+func (r *Registry) CodeMap() CodeMap {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	m := make(CodeMap, len(r.errs))
+	for _, e := range r.errs {
+		m[e.def.Code] = e.def.GrpcCode
+	}
+	return m
+}
+
+// Check returns an error when a code is not registered.
+func (r *Registry) Check(code interface{}) error {
+	if _, ok := r.Lookup(code); !ok {
+		return fmt.Errorf("grpcerrors: code %v is not registered", code)
+	}
+	return nil
+}
+
+func (r *Registry) publicMessage(err *fail.Error) (string, bool) {
+	r.mu.RLock()
+	e, ok := r.byCode[err.Code]
+	r.mu.RUnlock()
+	if !ok || e.tmpl == nil {
+		return "", false
+	}
+	var buf bytes.Buffer
+	if tErr := e.tmpl.Execute(&buf, map[string]interface{}(err.Params)); tErr != nil {
+		return "", false
+	}
+	return buf.String(), true
+}
+
+type catalogEntry struct {
+	Code          string `json:"code"`
+	GrpcCode      string `json:"grpc_code"`
+	Description   string `json:"description,omitempty"`
+	Retryable     bool   `json:"retryable"`
+	PublicMessage string `json:"public_message,omitempty"`
+}
+
+func (r *Registry) catalog() []catalogEntry {
+	defs := r.Definitions()
+	entries := make([]catalogEntry, 0, len(defs))
+	for _, def := range defs {
+		entries = append(entries, catalogEntry{
+			Code:          fmt.Sprint(def.Code),
+			GrpcCode:      grpcCodeName(def.GrpcCode),
+			Description:   def.Description,
+			Retryable:     def.Retryable,
+			PublicMessage: def.PublicMessage,
+		})
+	}
+	return entries
+}
+
+// WriteJSON writes a catalog of registered errors as JSON.
+func (r *Registry) WriteJSON(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(r.catalog())
+}
+
+// WriteMarkdown writes a catalog of registered errors as a Markdown table.
+func (r *Registry) WriteMarkdown(w io.Writer) error {
+	var buf bytes.Buffer
+	buf.WriteString("| Code | gRPC code | Retryable | Description | Message |\n")
+	buf.WriteString("| --- | --- | --- | --- | --- |\n")
+	for _, e := range r.catalog() {
+		retryable := "no"
+		if e.Retryable {
+			retryable = "yes"
+		}
+		fmt.Fprintf(&buf, "| `%s` | `%s` | %s | %s | %s |\n",
+			e.Code, e.GrpcCode, retryable, escapeMarkdownCell(e.Description), escapeMarkdownCell(e.PublicMessage))
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
+
+func escapeMarkdownCell(s string) string {
+	s = strings.Replace(s, "|", `\|`, -1)
+	return strings.Replace(s, "\n", "<br>", -1)
+}
+
+func grpcCodeName(code codes.Code) string {
+	for name, c := range grpcCodeByName {
+		if c == code {
+			return name
+		}
+	}
+	return code.String()
+}
+
+// WithRegistry returns a new error handler function for mapping status codes to gRPC's one with registered errors.
+// A message of a built status is a public message of a registered error, and messages of errors are never sent.
+// Errors with unregistered codes are passed to onUnregistered unless it is nil, and they are not mapped.
+func WithRegistry(r *Registry, onUnregistered FailHandlerFunc, detailFns ...StatusDetailsFunc) interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+} {
+	return WithFailHandler(func(c context.Context, err *fail.Error) error {
+		def, ok := r.Lookup(err.Code)
+		if !ok {
+			if onUnregistered != nil {
+				return onUnregistered(c, err)
+			}
+			return err
+		}
+		msg, ok := r.publicMessage(err)
+		if !ok {
+			msg = def.Description
+		}
+		if msg == "" {
+			msg = def.GrpcCode.String()
+		}
+		return newStatusError(c, def.GrpcCode, msg, err, detailFns)
+	})
+}
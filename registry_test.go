@@ -0,0 +1,205 @@
+package grpcerrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/srvc/grpc-errors/testing"
+)
+
+func newTestRegistry(t *testing.T) *Registry {
+	r := NewRegistry()
+	err := r.Register(
+		ErrorDefinition{
+			Code:          50,
+			GrpcCode:      codes.PermissionDenied,
+			Description:   "The user cannot access the resource.",
+			PublicMessage: "You cannot access {{.id}}.",
+		},
+		ErrorDefinition{
+			Code:        "rate_limited",
+			GrpcCode:    codes.ResourceExhausted,
+			Description: "Too many requests | per user.",
+			Retryable:   true,
+		},
+	)
+	if err != nil {
+		t.Fatalf("Failed to register errors: %v", err)
+	}
+	return r
+}
+
+func Test_Registry_Register_WhenACodeIsDuplicated(t *testing.T) {
+	r := newTestRegistry(t)
+
+	if err := r.Register(ErrorDefinition{Code: 50, GrpcCode: codes.NotFound}); err == nil {
+		t.Error("Register should return an error")
+	}
+}
+
+func Test_Registry_Register_WhenItFails(t *testing.T) {
+	cases := []struct {
+		test string
+		defs []ErrorDefinition
+	}{
+		{
+			test: "registered code",
+			defs: []ErrorDefinition{{Code: 60, GrpcCode: codes.NotFound}, {Code: 50, GrpcCode: codes.NotFound}},
+		},
+		{
+			test: "code given more than once",
+			defs: []ErrorDefinition{{Code: 60, GrpcCode: codes.NotFound}, {Code: 60, GrpcCode: codes.Internal}},
+		},
+		{
+			test: "invalid template",
+			defs: []ErrorDefinition{{Code: 60, GrpcCode: codes.NotFound}, {Code: 61, PublicMessage: "{{.id"}},
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			r := newTestRegistry(t)
+
+			if err := r.Register(c.defs...); err == nil {
+				t.Error("Register should return an error")
+			}
+
+			if got, want := len(r.Definitions()), 2; got != want {
+				t.Errorf("The registry has %d definitions, want %d", got, want)
+			}
+
+			if _, ok := r.Lookup(60); ok {
+				t.Error("Definitions given with an invalid one should not be registered")
+			}
+		})
+	}
+}
+
+func Test_Registry_WriteMarkdown(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newTestRegistry(t).WriteMarkdown(&buf); err != nil {
+		t.Fatalf("Failed to write a catalog: %v", err)
+	}
+
+	want := "| Code | gRPC code | Retryable | Description | Message |\n" +
+		"| --- | --- | --- | --- | --- |\n" +
+		"| `50` | `PERMISSION_DENIED` | no | The user cannot access the resource. | You cannot access {{.id}}. |\n" +
+		"| `rate_limited` | `RESOURCE_EXHAUSTED` | yes | Too many requests \\| per user. |  |\n"
+
+	if got := buf.String(); got != want {
+		t.Errorf("Written catalog is\n%s\nwant\n%s", got, want)
+	}
+}
+
+func Test_Registry_WriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := newTestRegistry(t).WriteJSON(&buf); err != nil {
+		t.Fatalf("Failed to write a catalog: %v", err)
+	}
+
+	var got []map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &got); err != nil {
+		t.Fatalf("Failed to decode a catalog: %v", err)
+	}
+
+	want := []map[string]interface{}{
+		{"code": "50", "grpc_code": "PERMISSION_DENIED", "description": "The user cannot access the resource.", "retryable": false, "public_message": "You cannot access {{.id}}."},
+		{"code": "rate_limited", "grpc_code": "RESOURCE_EXHAUSTED", "description": "Too many requests | per user.", "retryable": true},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Written catalog is %v, want %v", got, want)
+	}
+}
+
+func Test_UnaryServerInterceptor_WithRegistry(t *testing.T) {
+	cases := []struct {
+		test         string
+		server       errorstesting.TestServiceServer
+		code         codes.Code
+		message      string
+		unregistered bool
+	}{
+		{
+			test:    "registered error with public message",
+			server:  &errorWithAnnotationsService{Code: 50},
+			code:    codes.PermissionDenied,
+			message: "You cannot access 1.",
+		},
+		{
+			test:    "registered error without params used in public message",
+			server:  &errorWithStatusService{Code: 50},
+			code:    codes.PermissionDenied,
+			message: "The user cannot access the resource.",
+		},
+		{
+			test:    "registered error without public message and description",
+			server:  &errorWithStatusService{Code: 52},
+			code:    codes.NotFound,
+			message: "NotFound",
+		},
+		{
+			test:         "unregistered error",
+			server:       &errorWithStatusService{Code: 51},
+			code:         codes.Unknown,
+			message:      "This error has a status code",
+			unregistered: true,
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			var unregistered bool
+
+			r := newTestRegistry(t)
+			r.MustRegister(ErrorDefinition{Code: 52, GrpcCode: codes.NotFound})
+
+			ctx := errorstesting.CreateTestContext(t)
+			ctx.Service = c.server
+			ctx.AddUnaryServerInterceptor(
+				UnaryServerInterceptor(
+					WithRegistry(r, func(_ context.Context, err *fail.Error) error {
+						unregistered = true
+						return err
+					}),
+				),
+			)
+			ctx.Setup()
+			defer ctx.Teardown()
+
+			_, err := ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+			st := status.Convert(err)
+
+			if got, want := st.Code(), c.code; got != want {
+				t.Errorf("The returned error has error code %v, want %v", got, want)
+			}
+
+			if got, want := st.Message(), c.message; got != want {
+				t.Errorf("The returned error has message %q, want %q", got, want)
+			}
+
+			if got, want := unregistered, c.unregistered; got != want {
+				t.Errorf("The unregistered handler is called: got %t, want %t", got, want)
+			}
+		})
+	}
+}
+
+func Test_Registry_Check(t *testing.T) {
+	r := newTestRegistry(t)
+
+	if err := r.Check("rate_limited"); err != nil {
+		t.Errorf("Check should not return errors for a registered code: %v", err)
+	}
+
+	if err := r.Check(99); err == nil {
+		t.Error("Check should return an error for an unregistered code")
+	}
+}
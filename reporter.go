@@ -0,0 +1,75 @@
+package grpcerrors
+
+import (
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+)
+
+// Report is a set of information about an error that should be reported.
+type Report struct {
+	// Error is a reported error. Its stack trace is recorded from the point where it was created.
+	Error *fail.Error
+	// Method is a full method name.
+	Method string
+	// Request is a request message. It is the last received message on stream servers.
+	Request interface{}
+	// PeerAddr is an address of a client.
+	PeerAddr string
+	// Metadata is incoming metadata of a request.
+	Metadata metadata.MD
+	// Stream is a record of messages on a stream server. It is nil on unary servers.
+	Stream *StreamRecord
+}
+
+// Reporter is the interface that reports errors to an external service.
+type Reporter interface {
+	Report(context.Context, *Report)
+}
+
+type reporterHandler struct {
+	r Reporter
+}
+
+func (h *reporterHandler) HandleUnaryServerError(c context.Context, req interface{}, info *grpc.UnaryServerInfo, err error) error {
+	h.report(c, info.FullMethod, req, err)
+	return err
+}
+
+func (h *reporterHandler) HandleStreamServerError(c context.Context, req interface{}, resp interface{}, info *grpc.StreamServerInfo, err error) error {
+	h.report(c, info.FullMethod, req, err)
+	return err
+}
+
+func (h *reporterHandler) report(c context.Context, method string, req interface{}, err error) {
+	fErr := fail.Unwrap(err)
+	if fErr == nil || fErr.Ignorable {
+		return
+	}
+	r := &Report{
+		Error:   fErr,
+		Method:  method,
+		Request: req,
+	}
+	if p, ok := peer.FromContext(c); ok && p.Addr != nil {
+		r.PeerAddr = p.Addr.String()
+	}
+	if md, ok := metadata.FromIncomingContext(c); ok {
+		r.Metadata = md
+	}
+	if record, ok := StreamRecordFromContext(c); ok {
+		r.Stream = record
+	}
+	h.r.Report(c, r)
+}
+
+// WithReporter returns a new error handler that reports errors annotated with the reportability to r.
+// It does not change handled errors.
+func WithReporter(r Reporter) interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+} {
+	return &reporterHandler{r: r}
+}
@@ -0,0 +1,109 @@
+package grpcerrors
+
+import (
+	"fmt"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// PublicMessageParam is a key of fail.Error params that holds a message safe to be sent to clients.
+const PublicMessageParam = "grpcerrors.public_message"
+
+// LogFieldErrorID is a log field key of an opaque error ID assigned by sanitizers.
+const LogFieldErrorID = "error.id"
+
+// WithPublicMessage annotates an error with a message that is sent to clients instead of sanitized messages.
+func WithPublicMessage(msg string) fail.Annotator {
+	return fail.WithParam(PublicMessageParam, msg)
+}
+
+// SanitizePolicy maps gRPC's `codes.Code`s to generic messages that replace messages of statuses with the codes.
+// Statuses with codes not contained in the policy are sent as they are.
+type SanitizePolicy map[codes.Code]string
+
+// DefaultSanitizePolicy is a SanitizePolicy that sanitizes codes.Internal and codes.Unknown.
+var DefaultSanitizePolicy = SanitizePolicy{
+	codes.Internal: "internal error",
+	codes.Unknown:  "unknown error",
+}
+
+type sanitizingHandler struct {
+	mapper interface {
+		UnaryServerErrorHandler
+		StreamServerErrorHandler
+	}
+	policy SanitizePolicy
+	l      Logger
+}
+
+func (h *sanitizingHandler) HandleUnaryServerError(c context.Context, req interface{}, info *grpc.UnaryServerInfo, err error) error {
+	mapped := err
+	if h.mapper != nil {
+		mapped = h.mapper.HandleUnaryServerError(c, req, info, err)
+	}
+	return h.sanitize(c, info.FullMethod, err, mapped)
+}
+
+func (h *sanitizingHandler) HandleStreamServerError(c context.Context, req interface{}, resp interface{}, info *grpc.StreamServerInfo, err error) error {
+	mapped := err
+	if h.mapper != nil {
+		mapped = h.mapper.HandleStreamServerError(c, req, resp, info, err)
+	}
+	return h.sanitize(c, info.FullMethod, err, mapped)
+}
+
+func (h *sanitizingHandler) wrappedHandlers(fullMethod string) []interface{} {
+	if h.mapper == nil {
+		return nil
+	}
+	return []interface{}{h.mapper}
+}
+
+func (h *sanitizingHandler) sanitize(c context.Context, method string, err, mapped error) error {
+	if mapped == nil {
+		return nil
+	}
+	st := status.Convert(mapped)
+	msg, ok := h.policy[st.Code()]
+	if !ok {
+		return mapped
+	}
+
+	id := newRandomID()
+	if fErr := fail.Unwrap(err); fErr != nil {
+		if public, ok := fErr.Params[PublicMessageParam].(string); ok {
+			msg = public
+		}
+	}
+
+	if h.l != nil {
+		h.l.Log(c, logLevel(err), st.Message(), map[string]interface{}{
+			LogFieldErrorID:  id,
+			LogFieldMethod:   method,
+			LogFieldGrpcCode: st.Code().String(),
+		})
+	}
+
+	return status.Error(st.Code(), fmt.Sprintf("%s (error id: %s)", msg, id))
+}
+
+// WithSanitizer returns a new error handler that replaces messages of statuses with generic ones by policy.
+// mapper is an error handler that maps errors to gRPC statuses, such as WithCodeMap,
+// and errors are passed through without mapping when mapper is nil.
+// A replaced message has an opaque error ID, and an original message is logged with the ID by l unless it is nil.
+// Messages are logged at the same levels as WithLogger.
+// A message annotated by WithPublicMessage is used instead of a generic one.
+// Status details are dropped from sanitized statuses since they might have internal information.
+func WithSanitizer(policy SanitizePolicy, l Logger, mapper interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+}) interface {
+	UnaryServerErrorHandler
+	StreamServerErrorHandler
+} {
+	return &sanitizingHandler{mapper: mapper, policy: policy, l: l}
+}
@@ -0,0 +1,135 @@
+package grpcerrors
+
+import (
+	"bytes"
+	"errors"
+	"log"
+	"strings"
+	"testing"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/srvc/grpc-errors/testing"
+)
+
+type publicMessageService struct {
+	errorstesting.UnimplementedTestServiceServer
+}
+
+func (s *publicMessageService) EmptyCall(context.Context, *errorstesting.Empty) (*errorstesting.Empty, error) {
+	return nil, fail.Wrap(
+		errors.New("dial tcp 10.0.0.1:5432: connection refused"),
+		fail.WithCode(60),
+		WithPublicMessage("please retry later"),
+	)
+}
+
+func Test_UnaryServerInterceptor_WithSanitizer(t *testing.T) {
+	cases := []struct {
+		test    string
+		server  errorstesting.TestServiceServer
+		code    codes.Code
+		message string
+		level   string
+	}{
+		{
+			test:    "internal error",
+			server:  &errorWithStatusService{Code: 60},
+			code:    codes.Internal,
+			message: "internal error (error id: ",
+			level:   "[ERROR]",
+		},
+		{
+			test:    "not wrapped error",
+			server:  &errorService{},
+			code:    codes.Unknown,
+			message: "unknown error (error id: ",
+			level:   "[ERROR]",
+		},
+		{
+			test:    "ignored error",
+			server:  &ignoredErrorService{},
+			code:    codes.Unknown,
+			message: "unknown error (error id: ",
+			level:   "[WARN]",
+		},
+		{
+			test:    "internal error with public message",
+			server:  &publicMessageService{},
+			code:    codes.Internal,
+			message: "please retry later (error id: ",
+			level:   "[ERROR]",
+		},
+		{
+			test:    "error not sanitized",
+			server:  &errorWithStatusService{Code: 50},
+			code:    codes.PermissionDenied,
+			message: "This error has a status code",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			var buf bytes.Buffer
+
+			ctx := errorstesting.CreateTestContext(t)
+			ctx.Service = c.server
+			ctx.AddUnaryServerInterceptor(
+				UnaryServerInterceptor(
+					WithSanitizer(
+						DefaultSanitizePolicy,
+						NewStdLogger(log.New(&buf, "", 0)),
+						WithCodeMap(CodeMap{50: codes.PermissionDenied, 60: codes.Internal}),
+					),
+				),
+			)
+			ctx.Setup()
+			defer ctx.Teardown()
+
+			_, err := ctx.Client.EmptyCall(context.Background(), &errorstesting.Empty{})
+
+			st := status.Convert(err)
+
+			if got, want := st.Code(), c.code; got != want {
+				t.Errorf("The returned error has error code %v, want %v", got, want)
+			}
+
+			if got, want := st.Message(), c.message; !strings.HasPrefix(got, want) {
+				t.Errorf("The returned error has message %q, want prefix %q", got, want)
+			}
+
+			if got, want := buf.Len() > 0, c.level != ""; got != want {
+				t.Errorf("The original message is logged: got %t, want %t", got, want)
+			}
+
+			if c.level != "" {
+				if !strings.HasPrefix(buf.String(), c.level) {
+					t.Errorf("The log %q should have level %s", buf.String(), c.level)
+				}
+
+				id := strings.TrimSuffix(strings.TrimPrefix(st.Message(), c.message), ")")
+				if !strings.Contains(buf.String(), "error.id="+id) {
+					t.Errorf("The log %q should contain the error id %q", buf.String(), id)
+				}
+			}
+		})
+	}
+}
+
+func Test_newRandomID_WhenRandomSourceFails(t *testing.T) {
+	defer func(read func([]byte) (int, error)) { randRead = read }(randRead)
+	randRead = func([]byte) (int, error) { return 0, errors.New("no entropy") }
+
+	id1, id2 := newRandomID(), newRandomID()
+
+	if id1 == strings.Repeat("0", 32) {
+		t.Errorf("The ID should not be zero: %s", id1)
+	}
+
+	if id1 == id2 {
+		t.Errorf("IDs should be unique: %s, %s", id1, id2)
+	}
+}
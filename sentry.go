@@ -0,0 +1,247 @@
+package grpcerrors
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"log"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+)
+
+// SentryEvent is an event payload of the Sentry store API.
+type SentryEvent struct {
+	EventID   string                 `json:"event_id"`
+	Timestamp string                 `json:"timestamp"`
+	Level     string                 `json:"level"`
+	Platform  string                 `json:"platform"`
+	Message   string                 `json:"message"`
+	Exception *SentryException       `json:"exception,omitempty"`
+	Tags      map[string]string      `json:"tags,omitempty"`
+	Extra     map[string]interface{} `json:"extra,omitempty"`
+	User      *SentryUser            `json:"user,omitempty"`
+}
+
+// SentryException is an exception interface of Sentry events.
+type SentryException struct {
+	Values []SentryExceptionValue `json:"values"`
+}
+
+// SentryExceptionValue is an exception of Sentry events.
+type SentryExceptionValue struct {
+	Type       string            `json:"type"`
+	Value      string            `json:"value"`
+	Stacktrace *SentryStacktrace `json:"stacktrace,omitempty"`
+}
+
+// SentryStacktrace is a stack trace of Sentry events. Frames are ordered from oldest to newest.
+type SentryStacktrace struct {
+	Frames []SentryFrame `json:"frames"`
+}
+
+// SentryFrame is a stack frame of Sentry events.
+type SentryFrame struct {
+	Function string `json:"function"`
+	Filename string `json:"filename"`
+	Lineno   int64  `json:"lineno"`
+}
+
+// DefaultSentryTimeout is a default timeout for sending an event to Sentry.
+const DefaultSentryTimeout = 10 * time.Second
+
+// sentryFilteredValue replaces values of sensitive metadata in Sentry events.
+const sentryFilteredValue = "[Filtered]"
+
+// sentrySensitiveMetadataKeys are substrings of metadata keys whose values are filtered from Sentry events.
+var sentrySensitiveMetadataKeys = []string{"auth", "cookie", "token", "secret", "password", "session", "api-key", "apikey"}
+
+// SentryUser is a user interface of Sentry events.
+type SentryUser struct {
+	IPAddress string `json:"ip_address,omitempty"`
+}
+
+// NewSentryEvent builds a SentryEvent from a report.
+// Values of metadata that look sensitive, such as authorization and cookie, are filtered.
+func NewSentryEvent(r *Report) *SentryEvent {
+	err := r.Error
+	ev := &SentryEvent{
+		EventID:   newRandomID(),
+		Timestamp: time.Now().UTC().Format("2006-01-02T15:04:05"),
+		Level:     "error",
+		Platform:  "go",
+		Message:   err.Error(),
+		Tags:      map[string]string{"grpc.method": r.Method},
+		Extra:     map[string]interface{}{},
+	}
+
+	var rootType string
+	if err.Err != nil {
+		rootType = reflect.TypeOf(err.Err).String()
+	}
+	st := &SentryStacktrace{Frames: make([]SentryFrame, 0, len(err.StackTrace))}
+	for i := len(err.StackTrace) - 1; i >= 0; i-- {
+		f := err.StackTrace[i]
+		st.Frames = append(st.Frames, SentryFrame{Function: f.Func, Filename: f.File, Lineno: f.Line})
+	}
+	ev.Exception = &SentryException{
+		Values: []SentryExceptionValue{{Type: rootType, Value: err.Error(), Stacktrace: st}},
+	}
+
+	if err.Code != nil {
+		ev.Tags["error.code"] = fmt.Sprint(err.Code)
+	}
+	if len(err.Tags) > 0 {
+		ev.Extra["error.tags"] = sentryExtraValue(err.Tags)
+	}
+	for k, v := range err.Params {
+		ev.Extra["error.params."+k] = sentryExtraValue(v)
+	}
+	if r.Request != nil {
+		ev.Extra["grpc.request"] = sentryExtraValue(r.Request)
+	}
+	if len(r.Metadata) > 0 {
+		ev.Extra["grpc.metadata"] = filterSentryMetadata(r.Metadata)
+	}
+	if r.PeerAddr != "" {
+		ip := r.PeerAddr
+		if i := strings.LastIndex(ip, ":"); i >= 0 {
+			ip = strings.Trim(ip[:i], "[]")
+		}
+		ev.User = &SentryUser{IPAddress: ip}
+	}
+	return ev
+}
+
+// sentryExtraValue returns v when it can be encoded as JSON, and its formatted string otherwise.
+func sentryExtraValue(v interface{}) interface{} {
+	if _, err := json.Marshal(v); err != nil {
+		return fmt.Sprintf("%+v", v)
+	}
+	return v
+}
+
+// SentryReporter is a Reporter that sends events to the Sentry store API.
+type SentryReporter struct {
+	// Client is used for sending events. http.DefaultClient is used when it is nil.
+	Client *http.Client
+	// ErrorLog is used for logging failures on sending events. The standard logger is used when it is nil.
+	ErrorLog *log.Logger
+	// Timeout bounds sending an event. DefaultSentryTimeout is used when it is zero.
+	Timeout time.Duration
+
+	storeURL  string
+	publicKey string
+	wg        sync.WaitGroup
+}
+
+// NewSentryReporter returns a new SentryReporter with a Sentry DSN like "https://<key>@<host>/<project>".
+func NewSentryReporter(dsn string) (*SentryReporter, error) {
+	u, err := url.Parse(dsn)
+	if err != nil {
+		return nil, fmt.Errorf("grpcerrors: invalid Sentry DSN: %v", err)
+	}
+	if u.User == nil || u.User.Username() == "" {
+		return nil, fmt.Errorf("grpcerrors: Sentry DSN has no public key")
+	}
+	i := strings.LastIndex(u.Path, "/")
+	projectID := u.Path[i+1:]
+	if projectID == "" {
+		return nil, fmt.Errorf("grpcerrors: Sentry DSN has no project ID")
+	}
+	storeURL := url.URL{
+		Scheme: u.Scheme,
+		Host:   u.Host,
+		Path:   fmt.Sprintf("%s/api/%s/store/", u.Path[:i], projectID),
+	}
+	return &SentryReporter{
+		storeURL:  storeURL.String(),
+		publicKey: u.User.Username(),
+	}, nil
+}
+
+// Report implements Reporter. It sends an event asynchronously,
+// with a context detached from c so that sending neither blocks nor is canceled with an RPC.
+// Use Flush to wait for events being sent, for example before a server exits.
+func (r *SentryReporter) Report(c context.Context, report *Report) {
+	ev := NewSentryEvent(report)
+	body, err := json.Marshal(ev)
+	if err != nil {
+		r.logf("grpcerrors: failed to encode extra data of an event for Sentry: %v", err)
+		ev.Extra = nil
+		if body, err = json.Marshal(ev); err != nil {
+			r.logf("grpcerrors: failed to encode an event for Sentry: %v", err)
+			return
+		}
+	}
+	timeout := r.Timeout
+	if timeout == 0 {
+		timeout = DefaultSentryTimeout
+	}
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		c, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		if err := r.send(c, body); err != nil {
+			r.logf("grpcerrors: failed to send an event to Sentry: %v", err)
+		}
+	}()
+}
+
+// Flush waits until all events being sent are sent.
+func (r *SentryReporter) Flush() {
+	r.wg.Wait()
+}
+
+func (r *SentryReporter) send(c context.Context, body []byte) error {
+	req, err := http.NewRequest(http.MethodPost, r.storeURL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req = req.WithContext(c)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Sentry-Auth", fmt.Sprintf("Sentry sentry_version=7, sentry_client=grpc-errors, sentry_key=%s", r.publicKey))
+
+	client := r.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("unexpected status %s", resp.Status)
+	}
+	return nil
+}
+
+func filterSentryMetadata(md metadata.MD) metadata.MD {
+	filtered := make(metadata.MD, len(md))
+	for k, vs := range md {
+		filtered[k] = vs
+		for _, sk := range sentrySensitiveMetadataKeys {
+			if strings.Contains(strings.ToLower(k), sk) {
+				filtered[k] = []string{sentryFilteredValue}
+				break
+			}
+		}
+	}
+	return filtered
+}
+
+func (r *SentryReporter) logf(format string, args ...interface{}) {
+	if r.ErrorLog == nil {
+		log.Printf(format, args...)
+		return
+	}
+	r.ErrorLog.Printf(format, args...)
+}
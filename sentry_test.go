@@ -0,0 +1,186 @@
+package grpcerrors
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/srvc/fail/v4"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/metadata"
+
+	"github.com/srvc/grpc-errors/testing"
+)
+
+func Test_UnaryServerInterceptor_WithReporter_SentryReporter(t *testing.T) {
+	var (
+		gotPath string
+		gotAuth string
+		events  []SentryEvent
+	)
+
+	sentry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotPath = r.URL.Path
+		gotAuth = r.Header.Get("X-Sentry-Auth")
+		var ev SentryEvent
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			t.Errorf("Failed to decode an event: %v", err)
+		}
+		events = append(events, ev)
+	}))
+	defer sentry.Close()
+
+	reporter, err := NewSentryReporter(strings.Replace(sentry.URL, "http://", "http://publickey@", 1) + "/42")
+	if err != nil {
+		t.Fatalf("Failed to create a reporter: %v", err)
+	}
+
+	for _, svc := range []errorstesting.TestServiceServer{
+		&errorWithAnnotationsService{Code: 50},
+		&failService{},
+		&ignoredErrorService{},
+	} {
+		ctx := errorstesting.CreateTestContext(t)
+		ctx.Service = svc
+		ctx.TCP = true
+		ctx.AddUnaryServerInterceptor(UnaryServerInterceptor(WithReporter(reporter)))
+		ctx.Setup()
+		c := metadata.AppendToOutgoingContext(context.Background(), "x-request-id", "req-1", "authorization", "Bearer secret-token")
+		ctx.Client.EmptyCall(c, &errorstesting.Empty{})
+		ctx.Teardown()
+	}
+	reporter.Flush()
+
+	if got, want := gotPath, "/api/42/store/"; got != want {
+		t.Errorf("Events are sent to %q, want %q", got, want)
+	}
+
+	if !strings.Contains(gotAuth, "sentry_key=publickey") {
+		t.Errorf("X-Sentry-Auth header %q should contain the public key", gotAuth)
+	}
+
+	if got, want := len(events), 1; got != want {
+		t.Fatalf("Sent %d events, want %d", got, want)
+	}
+
+	ev := events[0]
+
+	if got, want := ev.Message, "This error is wrapped with fail.Error"; got != want {
+		t.Errorf("The event has message %q, want %q", got, want)
+	}
+
+	if got, want := ev.Tags["grpc.method"], "/errorstesting.TestService/EmptyCall"; got != want {
+		t.Errorf("The event has method %q, want %q", got, want)
+	}
+
+	if ev.Exception == nil || len(ev.Exception.Values[0].Stacktrace.Frames) == 0 {
+		t.Error("The event should have a stack trace")
+	}
+
+	md, ok := ev.Extra["grpc.metadata"].(map[string]interface{})
+	if !ok || md["x-request-id"] == nil {
+		t.Errorf("The event should have incoming metadata: %v", ev.Extra["grpc.metadata"])
+	}
+
+	if got, want := fmt.Sprint(md["authorization"]), "[[Filtered]]"; got != want {
+		t.Errorf("The event has authorization metadata %s, want %s", got, want)
+	}
+
+	if ev.User == nil || ev.User.IPAddress != "127.0.0.1" {
+		t.Errorf("The event should have a peer address: %v", ev.User)
+	}
+}
+
+func Test_SentryReporter_Report(t *testing.T) {
+	received := make(chan struct{}, 1)
+	block := make(chan struct{})
+	sentry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received <- struct{}{}
+		select {
+		case <-block:
+		case <-r.Context().Done():
+		}
+	}))
+	defer sentry.Close()
+	defer close(block)
+
+	reporter, err := NewSentryReporter(strings.Replace(sentry.URL, "http://", "http://publickey@", 1) + "/42")
+	if err != nil {
+		t.Fatalf("Failed to create a reporter: %v", err)
+	}
+	reporter.Timeout = 50 * time.Millisecond
+	reporter.ErrorLog = log.New(ioutil.Discard, "", 0)
+
+	c, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	start := time.Now()
+	reporter.Report(c, &Report{Error: fail.Unwrap(fail.New("error")), Method: "/errorstesting.TestService/EmptyCall"})
+	if d := time.Since(start); d >= reporter.Timeout {
+		t.Errorf("Report blocked for %v", d)
+	}
+
+	select {
+	case <-received:
+	case <-time.After(time.Second):
+		t.Fatal("An event should be sent even when a context of an RPC is canceled")
+	}
+
+	done := make(chan struct{})
+	go func() {
+		reporter.Flush()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Sending an event should time out")
+	}
+}
+
+func Test_HTTPMiddleware_WithReporter_SentryReporter(t *testing.T) {
+	events := make(chan SentryEvent, 1)
+	sentry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var ev SentryEvent
+		if err := json.NewDecoder(r.Body).Decode(&ev); err != nil {
+			t.Errorf("Failed to decode an event: %v", err)
+		}
+		events <- ev
+	}))
+	defer sentry.Close()
+
+	reporter, err := NewSentryReporter(strings.Replace(sentry.URL, "http://", "http://publickey@", 1) + "/42")
+	if err != nil {
+		t.Fatalf("Failed to create a reporter: %v", err)
+	}
+	reporter.ErrorLog = log.New(ioutil.Discard, "", 0)
+
+	handler := HTTPMiddleware(nil, WithReporter(reporter))("/users/{id}", func(w http.ResponseWriter, r *http.Request) error {
+		return fail.Wrap(fail.New("error"), fail.WithParam("callback", func() {}))
+	})
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/users/1", nil))
+	reporter.Flush()
+
+	select {
+	case ev := <-events:
+		if got, want := ev.Tags["grpc.method"], "/users/{id}"; got != want {
+			t.Errorf("The event has method %q, want %q", got, want)
+		}
+
+		if _, ok := ev.Extra["grpc.request"].(string); !ok {
+			t.Errorf("The request should be formatted as a string: %v", ev.Extra["grpc.request"])
+		}
+
+		if _, ok := ev.Extra["error.params.callback"].(string); !ok {
+			t.Errorf("The param should be formatted as a string: %v", ev.Extra["error.params.callback"])
+		}
+	default:
+		t.Fatal("An event should be sent even when a request cannot be encoded as JSON")
+	}
+}
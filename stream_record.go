@@ -0,0 +1,148 @@
+package grpcerrors
+
+import (
+	"sync"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// StreamMessages is a record of messages in one direction of a stream.
+type StreamMessages struct {
+	// Messages is the last messages in the order of occurrence.
+	Messages []interface{}
+	// Count is a total number of messages.
+	Count int
+	// Bytes is a total size of messages. Only messages implementing proto.Message are counted.
+	Bytes int
+	// FirstAt is a time when the first message was transferred.
+	FirstAt time.Time
+	// LastAt is a time when the last message was transferred.
+	LastAt time.Time
+}
+
+// Last returns the last message, or nil when no messages are recorded.
+func (m StreamMessages) Last() interface{} {
+	if len(m.Messages) == 0 {
+		return nil
+	}
+	return m.Messages[len(m.Messages)-1]
+}
+
+// StreamRecord is a record of messages sent and received on a stream server.
+type StreamRecord struct {
+	Sent     StreamMessages
+	Received StreamMessages
+}
+
+type streamRecordKey struct{}
+
+// StreamRecordFromContext returns a StreamRecord passed to stream server error handlers.
+func StreamRecordFromContext(c context.Context) (*StreamRecord, bool) {
+	r, ok := c.Value(streamRecordKey{}).(*StreamRecord)
+	return r, ok
+}
+
+type streamRecorder struct {
+	mu       sync.Mutex
+	record   StreamRecord
+	sent     messageRing
+	received messageRing
+}
+
+func (r *streamRecorder) recordSent(m interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.add(&r.record.Sent, &r.sent, m)
+}
+
+func (r *streamRecorder) recordReceived(m interface{}) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.add(&r.record.Received, &r.received, m)
+}
+
+func (r *streamRecorder) add(msgs *StreamMessages, ring *messageRing, m interface{}) {
+	now := time.Now()
+	if msgs.Count == 0 {
+		msgs.FirstAt = now
+	}
+	msgs.LastAt = now
+	msgs.Count++
+	if pm, ok := m.(proto.Message); ok {
+		msgs.Bytes += proto.Size(pm)
+	}
+	ring.add(m)
+}
+
+func (r *streamRecorder) snapshot() *StreamRecord {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	record := r.record
+	record.Sent.Messages = r.sent.messages()
+	record.Received.Messages = r.received.messages()
+	return &record
+}
+
+// messageRing keeps the last size messages. Once it is full, a new message overwrites the oldest one.
+type messageRing struct {
+	size int
+	buf  []interface{}
+	next int
+}
+
+func (r *messageRing) add(m interface{}) {
+	if len(r.buf) < r.size {
+		r.buf = append(r.buf, m)
+		return
+	}
+	r.buf[r.next] = m
+	r.next = (r.next + 1) % r.size
+}
+
+func (r *messageRing) messages() []interface{} {
+	msgs := make([]interface{}, 0, len(r.buf))
+	msgs = append(msgs, r.buf[r.next:]...)
+	return append(msgs, r.buf[:r.next]...)
+}
+
+type streamRecorderHandler struct {
+	maxMessages int
+}
+
+func (h *streamRecorderHandler) HandleStreamServerError(c context.Context, req interface{}, resp interface{}, info *grpc.StreamServerInfo, err error) error {
+	return err
+}
+
+// WithStreamRecorder returns a new error handler that makes stream server interceptors record messages with StreamRecord.
+// The last maxMessages messages are kept in each direction, and values less than 1 are regarded as 1
+// since the last messages are passed to error handlers as a request and a response.
+// Counts, sizes and timestamps of messages are also recorded. Interceptors keep only the last messages without it.
+// It also takes effect when it is wrapped by other handlers such as ForMethods.
+func WithStreamRecorder(maxMessages int) StreamServerErrorHandler {
+	return &streamRecorderHandler{maxMessages: maxMessages}
+}
+
+func findStreamRecorder(handlers []interface{}, fullMethod string) *streamRecorderHandler {
+	if h, ok := findHandler(handlers, fullMethod, func(h interface{}) bool {
+		_, ok := h.(*streamRecorderHandler)
+		return ok
+	}).(*streamRecorderHandler); ok {
+		return h
+	}
+	return nil
+}
+
+// newStreamRecorder returns nil when h is nil, that is, WithStreamRecorder is not used for a method.
+func newStreamRecorder(h *streamRecorderHandler) *streamRecorder {
+	if h == nil {
+		return nil
+	}
+	maxMessages := h.maxMessages
+	if maxMessages < 1 {
+		maxMessages = 1
+	}
+	return &streamRecorder{sent: messageRing{size: maxMessages}, received: messageRing{size: maxMessages}}
+}
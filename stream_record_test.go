@@ -0,0 +1,124 @@
+package grpcerrors
+
+import (
+	"errors"
+	"io"
+	"testing"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+
+	"github.com/srvc/grpc-errors/testing"
+)
+
+type fakeServerStream struct {
+	grpc.ServerStream
+	received int
+	maxRecv  int
+}
+
+func (s *fakeServerStream) Context() context.Context { return context.Background() }
+
+func (s *fakeServerStream) SendMsg(m interface{}) error { return nil }
+
+func (s *fakeServerStream) RecvMsg(m interface{}) error {
+	if s.received >= s.maxRecv {
+		return io.EOF
+	}
+	s.received++
+	return nil
+}
+
+type streamServerErrorHandlerFunc func(context.Context, interface{}, interface{}, *grpc.StreamServerInfo, error) error
+
+func (f streamServerErrorHandlerFunc) HandleStreamServerError(c context.Context, req, resp interface{}, info *grpc.StreamServerInfo, err error) error {
+	return f(c, req, resp, info, err)
+}
+
+func Test_StreamServerInterceptor_StreamRecord(t *testing.T) {
+	cases := []struct {
+		name     string
+		handlers []StreamServerErrorHandler
+		// wantMessages is zero when a StreamRecord should not be recorded.
+		wantMessages int
+	}{
+		{name: "default", wantMessages: 0},
+		{name: "WithStreamRecorder", handlers: []StreamServerErrorHandler{WithStreamRecorder(3)}, wantMessages: 3},
+		{name: "WithStreamRecorder(0)", handlers: []StreamServerErrorHandler{WithStreamRecorder(0)}, wantMessages: 1},
+		{
+			name:         "WithStreamRecorder wrapped by ForMethods",
+			handlers:     []StreamServerErrorHandler{ForMethods([]string{"/errorstesting.TestService/*"}, WithStreamRecorder(3))},
+			wantMessages: 3,
+		},
+		{
+			name:         "WithStreamRecorder wrapped by ForMethods for other methods",
+			handlers:     []StreamServerErrorHandler{ForMethods([]string{"/other.Service/*"}, WithStreamRecorder(3))},
+			wantMessages: 0,
+		},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			var (
+				gotRecord *StreamRecord
+				gotReq    interface{}
+			)
+			handlers := append(tc.handlers, streamServerErrorHandlerFunc(func(c context.Context, req, resp interface{}, info *grpc.StreamServerInfo, err error) error {
+				gotRecord, _ = StreamRecordFromContext(c)
+				gotReq = req
+				return err
+			}))
+			reqs := []*errorstesting.Empty{{}, {}, {}, {}}
+			errAborted := errors.New("aborted")
+
+			interceptor := StreamServerInterceptor(handlers...)
+			err := interceptor(nil, &fakeServerStream{maxRecv: len(reqs)}, &grpc.StreamServerInfo{FullMethod: "/errorstesting.TestService/BidiStreamCall"}, func(srv interface{}, stream grpc.ServerStream) error {
+				for _, req := range reqs {
+					if err := stream.RecvMsg(req); err != nil {
+						return err
+					}
+				}
+				if err := stream.RecvMsg(&errorstesting.Empty{}); err != io.EOF {
+					t.Fatalf("RecvMsg() returned %v, want io.EOF", err)
+				}
+				if err := stream.SendMsg(&errorstesting.Empty{}); err != nil {
+					return err
+				}
+				return errAborted
+			})
+
+			if got, want := err, errAborted; got != want {
+				t.Errorf("returned error is %v, want %v", got, want)
+			}
+			if got, want := gotReq, interface{}(reqs[len(reqs)-1]); got != want {
+				t.Errorf("request is %v, want the last received message", got)
+			}
+			if tc.wantMessages == 0 {
+				if gotRecord != nil {
+					t.Errorf("StreamRecordFromContext() returned %v, want nil", gotRecord)
+				}
+				return
+			}
+			if gotRecord == nil {
+				t.Fatal("StreamRecordFromContext() returned nil")
+			}
+			if got, want := gotRecord.Received.Count, len(reqs); got != want {
+				t.Errorf("Received.Count is %d, want %d", got, want)
+			}
+			if got, want := gotRecord.Sent.Count, 1; got != want {
+				t.Errorf("Sent.Count is %d, want %d", got, want)
+			}
+			if got, want := len(gotRecord.Received.Messages), tc.wantMessages; got != want {
+				t.Fatalf("Received.Messages has %d messages, want %d", got, want)
+			}
+			for i, m := range gotRecord.Received.Messages {
+				if got, want := m, interface{}(reqs[len(reqs)-tc.wantMessages+i]); got != want {
+					t.Errorf("Received.Messages[%d] is not reqs[%d]", i, len(reqs)-tc.wantMessages+i)
+				}
+			}
+			if gotRecord.Received.FirstAt.IsZero() || gotRecord.Received.LastAt.Before(gotRecord.Received.FirstAt) {
+				t.Errorf("Received timestamps are invalid: %v, %v", gotRecord.Received.FirstAt, gotRecord.Received.LastAt)
+			}
+		})
+	}
+}
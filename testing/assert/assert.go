@@ -0,0 +1,240 @@
+// Package assert provides test assertions for errors carrying gRPC statuses.
+package assert
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/srvc/grpc-errors/errorspb"
+)
+
+var update = flag.Bool("update-golden", false, "update golden files of gRPC statuses")
+
+// Code asserts that err has a gRPC status with the code.
+func Code(t testing.TB, err error, want codes.Code) bool {
+	t.Helper()
+	if got := status.Code(err); got != want {
+		t.Errorf("The error has gRPC code %v, want %v: %v", got, want, err)
+		return false
+	}
+	return true
+}
+
+// Message asserts that err has a gRPC status with the message.
+func Message(t testing.TB, err error, want string) bool {
+	t.Helper()
+	st, ok := fromError(t, err)
+	if !ok {
+		return false
+	}
+	if got := st.Message(); got != want {
+		t.Errorf("The error has gRPC message %q, want %q", got, want)
+		return false
+	}
+	return true
+}
+
+// HasDetail asserts that err has a gRPC status with a detail of the same type as typ.
+// When typ is a non-nil pointer, the found detail is copied into it. A typed nil pointer such as (*errdetails.BadRequest)(nil) is also accepted.
+func HasDetail(t testing.TB, err error, typ proto.Message) bool {
+	t.Helper()
+	if typ == nil {
+		t.Error("A detail type should not be nil")
+		return false
+	}
+	st, ok := fromError(t, err)
+	if !ok {
+		return false
+	}
+	d := findDetail(st, typ)
+	if d == nil {
+		t.Errorf("The error does not have a %s detail, got %s", proto.MessageName(typ), detailNames(st))
+		return false
+	}
+	if v := reflect.ValueOf(typ); !v.IsNil() {
+		v.Elem().Set(reflect.ValueOf(d).Elem())
+	}
+	return true
+}
+
+// Detail asserts that err has a gRPC status with a detail equal to want.
+func Detail(t testing.TB, err error, want proto.Message) bool {
+	t.Helper()
+	if want == nil {
+		t.Error("A wanted detail should not be nil")
+		return false
+	}
+	st, ok := fromError(t, err)
+	if !ok {
+		return false
+	}
+	got := findDetail(st, want)
+	if got == nil {
+		t.Errorf("The error does not have a %s detail, got %s", proto.MessageName(want), detailNames(st))
+		return false
+	}
+	if !proto.Equal(got, want) {
+		t.Errorf("The error has a different %s detail:\n%s", proto.MessageName(want), Diff(proto.MarshalTextString(want), proto.MarshalTextString(got)))
+		return false
+	}
+	return true
+}
+
+// AppCode asserts that err has a gRPC status with an errorspb.FailDetail carrying the application code.
+func AppCode(t testing.TB, err error, want string) bool {
+	t.Helper()
+	st, ok := fromError(t, err)
+	if !ok {
+		return false
+	}
+	d, _ := findDetail(st, (*errorspb.FailDetail)(nil)).(*errorspb.FailDetail)
+	if d == nil {
+		t.Errorf("The error does not have a %s detail, got %s", proto.MessageName((*errorspb.FailDetail)(nil)), detailNames(st))
+		return false
+	}
+	if got := d.Code; got != want {
+		t.Errorf("The error has application code %q, want %q", got, want)
+		return false
+	}
+	return true
+}
+
+// Golden asserts that err has a gRPC status equal to the one written in the golden file.
+// Golden files are updated when tests run with the -update-golden flag.
+func Golden(t testing.TB, err error, path string) bool {
+	t.Helper()
+	st, ok := fromError(t, err)
+	if !ok {
+		return false
+	}
+	got := FormatStatus(st)
+	if *update {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("Failed to create a directory for %s: %v", path, err)
+		}
+		if err := ioutil.WriteFile(path, []byte(got), 0644); err != nil {
+			t.Fatalf("Failed to update %s: %v", path, err)
+		}
+		return true
+	}
+	want, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Errorf("Failed to read %s: %v", path, err)
+		return false
+	}
+	if got != string(want) {
+		t.Errorf("The error does not match %s:\n%s", path, Diff(string(want), got))
+		return false
+	}
+	return true
+}
+
+// FormatStatus returns a text representation of st used in golden files.
+func FormatStatus(st *status.Status) string {
+	buf := new(bytes.Buffer)
+	fmt.Fprintf(buf, "code: %v\n", st.Code())
+	fmt.Fprintf(buf, "message: %q\n", st.Message())
+	for _, d := range st.Details() {
+		m, ok := d.(proto.Message)
+		if !ok {
+			fmt.Fprintf(buf, "detail: %v\n", d)
+			continue
+		}
+		fmt.Fprintf(buf, "detail: %s\n", proto.MessageName(m))
+		for _, line := range strings.SplitAfter(proto.MarshalTextString(m), "\n") {
+			if line != "" {
+				fmt.Fprintf(buf, "  %s", line)
+			}
+		}
+	}
+	return buf.String()
+}
+
+// Diff returns a line-based diff from want to got.
+// Removed lines are prefixed with "-", and added lines are prefixed with "+".
+func Diff(want, got string) string {
+	a := strings.Split(strings.TrimSuffix(want, "\n"), "\n")
+	b := strings.Split(strings.TrimSuffix(got, "\n"), "\n")
+
+	// lcs[i][j] is a length of the longest common subsequence of a[i:] and b[j:].
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	buf := new(bytes.Buffer)
+	i, j := 0, 0
+	for i < len(a) || j < len(b) {
+		switch {
+		case i < len(a) && j < len(b) && a[i] == b[j]:
+			fmt.Fprintf(buf, "  %s\n", a[i])
+			i++
+			j++
+		case j < len(b) && (i == len(a) || lcs[i][j+1] > lcs[i+1][j]):
+			fmt.Fprintf(buf, "+ %s\n", b[j])
+			j++
+		default:
+			fmt.Fprintf(buf, "- %s\n", a[i])
+			i++
+		}
+	}
+	return buf.String()
+}
+
+func fromError(t testing.TB, err error) (*status.Status, bool) {
+	t.Helper()
+	if err == nil {
+		t.Error("The error should not be nil")
+		return nil, false
+	}
+	st, ok := status.FromError(err)
+	if !ok {
+		t.Errorf("The error does not have a gRPC status: %v", err)
+		return nil, false
+	}
+	return st, true
+}
+
+func findDetail(st *status.Status, typ proto.Message) proto.Message {
+	want := reflect.TypeOf(typ)
+	for _, d := range st.Details() {
+		if reflect.TypeOf(d) == want {
+			return d.(proto.Message)
+		}
+	}
+	return nil
+}
+
+func detailNames(st *status.Status) string {
+	names := make([]string, 0, len(st.Details()))
+	for _, d := range st.Details() {
+		if m, ok := d.(proto.Message); ok {
+			names = append(names, proto.MessageName(m))
+		} else {
+			names = append(names, fmt.Sprint(d))
+		}
+	}
+	return "[" + strings.Join(names, ", ") + "]"
+}
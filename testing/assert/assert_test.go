@@ -0,0 +1,171 @@
+package assert
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/srvc/grpc-errors/errorspb"
+)
+
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (t *fakeT) Helper() {}
+
+func (t *fakeT) Errorf(format string, args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprintf(format, args...))
+}
+
+func (t *fakeT) Error(args ...interface{}) {
+	t.errors = append(t.errors, fmt.Sprint(args...))
+}
+
+func newStatusError(t *testing.T) error {
+	st, err := status.New(codes.InvalidArgument, "invalid request").WithDetails(
+		&errdetails.BadRequest{
+			FieldViolations: []*errdetails.BadRequest_FieldViolation{
+				{Field: "name", Description: "must not be empty"},
+			},
+		},
+		&errorspb.FailDetail{Code: "50", Messages: []string{"invalid request"}},
+	)
+	if err != nil {
+		t.Fatalf("Failed to create a status: %v", err)
+	}
+	return st.Err()
+}
+
+func Test_Assertions(t *testing.T) {
+	err := newStatusError(t)
+
+	cases := []struct {
+		test   string
+		assert func(t testing.TB) bool
+		ok     bool
+		msg    string
+	}{
+		{
+			test:   "Code",
+			assert: func(t testing.TB) bool { return Code(t, err, codes.InvalidArgument) },
+			ok:     true,
+		},
+		{
+			test:   "Code with a different code",
+			assert: func(t testing.TB) bool { return Code(t, err, codes.NotFound) },
+			msg:    "The error has gRPC code InvalidArgument, want NotFound",
+		},
+		{
+			test:   "Message",
+			assert: func(t testing.TB) bool { return Message(t, err, "invalid request") },
+			ok:     true,
+		},
+		{
+			test:   "Message with an error without status",
+			assert: func(t testing.TB) bool { return Message(t, errors.New("error"), "error") },
+			msg:    "The error does not have a gRPC status",
+		},
+		{
+			test:   "HasDetail",
+			assert: func(t testing.TB) bool { return HasDetail(t, err, &errdetails.BadRequest{}) },
+			ok:     true,
+		},
+		{
+			test:   "HasDetail with a missing detail",
+			assert: func(t testing.TB) bool { return HasDetail(t, err, &errdetails.RetryInfo{}) },
+			msg:    "The error does not have a google.rpc.RetryInfo detail, got [google.rpc.BadRequest, grpcerrors.FailDetail]",
+		},
+		{
+			test:   "HasDetail with a typed nil pointer",
+			assert: func(t testing.TB) bool { return HasDetail(t, err, (*errdetails.BadRequest)(nil)) },
+			ok:     true,
+		},
+		{
+			test:   "HasDetail with nil",
+			assert: func(t testing.TB) bool { return HasDetail(t, err, nil) },
+			msg:    "A detail type should not be nil",
+		},
+		{
+			test:   "Detail with nil",
+			assert: func(t testing.TB) bool { return Detail(t, err, nil) },
+			msg:    "A wanted detail should not be nil",
+		},
+		{
+			test: "Detail",
+			assert: func(t testing.TB) bool {
+				return Detail(t, err, &errdetails.BadRequest{
+					FieldViolations: []*errdetails.BadRequest_FieldViolation{
+						{Field: "name", Description: "must not be empty"},
+					},
+				})
+			},
+			ok: true,
+		},
+		{
+			test: "Detail with different content",
+			assert: func(t testing.TB) bool {
+				return Detail(t, err, &errdetails.BadRequest{
+					FieldViolations: []*errdetails.BadRequest_FieldViolation{
+						{Field: "age", Description: "must not be empty"},
+					},
+				})
+			},
+			msg: "-   field: \"age\"\n+   field: \"name\"",
+		},
+		{
+			test:   "AppCode",
+			assert: func(t testing.TB) bool { return AppCode(t, err, "50") },
+			ok:     true,
+		},
+		{
+			test:   "AppCode with a different code",
+			assert: func(t testing.TB) bool { return AppCode(t, err, "51") },
+			msg:    `The error has application code "50", want "51"`,
+		},
+		{
+			test:   "Golden",
+			assert: func(t testing.TB) bool { return Golden(t, err, "testdata/invalid_argument.golden") },
+			ok:     true,
+		},
+		{
+			test: "Golden with a different status",
+			assert: func(t testing.TB) bool {
+				return Golden(t, status.Error(codes.InvalidArgument, "invalid request"), "testdata/invalid_argument.golden")
+			},
+			msg: "- detail: google.rpc.BadRequest",
+		},
+	}
+
+	for _, c := range cases {
+		t.Run(c.test, func(t *testing.T) {
+			ft := &fakeT{TB: t}
+
+			if got, want := c.assert(ft), c.ok; got != want {
+				t.Errorf("The assertion returned %t, want %t", got, want)
+			}
+
+			if c.ok {
+				if len(ft.errors) > 0 {
+					t.Errorf("The assertion reported errors: %v", ft.errors)
+				}
+			} else if got := strings.Join(ft.errors, "\n"); !strings.Contains(got, c.msg) {
+				t.Errorf("The assertion reported %q, want to contain %q", got, c.msg)
+			}
+		})
+	}
+}
+
+func Test_Diff(t *testing.T) {
+	got := Diff("a\nb\nc\n", "a\nc\nd\n")
+	want := "  a\n- b\n  c\n+ d\n"
+	if got != want {
+		t.Errorf("Diff() returned %q, want %q", got, want)
+	}
+}
@@ -2,22 +2,41 @@ package errorstesting
 
 import (
 	"net"
+	"sync"
 	"testing"
 	"time"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/test/bufconn"
 )
 
+const bufconnSize = 1024 * 1024
+
 // TestContext is a testing helper for generating a gRPC server and a gRPC client.
 type TestContext struct {
 	t *testing.T
 
+	// ServerOpts are passed to a test server. They must not contain grpc.UnaryInterceptor or grpc.StreamInterceptor
+	// when interceptors are added with AddUnaryServerInterceptor or AddStreamServerInterceptor,
+	// since gRPC panics when an interceptor is set twice.
 	ServerOpts []grpc.ServerOption
+	// ClientOpts are passed to a test client. Interceptors added with AddUnaryClientInterceptor or AddStreamClientInterceptor
+	// replace ones set by grpc.WithUnaryInterceptor or grpc.WithStreamInterceptor.
 	ClientOpts []grpc.DialOption
 
+	// TCP makes a server listen on a local TCP port instead of an in-memory connection.
+	TCP bool
+
+	unaryServerInterceptors  []grpc.UnaryServerInterceptor
+	streamServerInterceptors []grpc.StreamServerInterceptor
+	unaryClientInterceptors  []grpc.UnaryClientInterceptor
+	streamClientInterceptors []grpc.StreamClientInterceptor
+
 	serverListener net.Listener
 	server         *grpc.Server
+	serverDone     chan struct{}
 	clientConn     *grpc.ClientConn
+	teardownOnce   sync.Once
 
 	Service TestServiceServer
 	Client  TestServiceClient
@@ -28,49 +47,100 @@ func CreateTestContext(t *testing.T) *TestContext {
 	return &TestContext{t: t}
 }
 
-// AddUnaryServerInterceptor sets interceptors to a test server.
+// AddUnaryServerInterceptor adds an interceptor to a test server.
+// Interceptors are called in the order they are added.
 func (c *TestContext) AddUnaryServerInterceptor(i grpc.UnaryServerInterceptor) {
-	c.ServerOpts = []grpc.ServerOption{
-		grpc.UnaryInterceptor(i),
-	}
+	c.unaryServerInterceptors = append(c.unaryServerInterceptors, i)
+}
+
+// AddStreamServerInterceptor adds an interceptor to a test server.
+// Interceptors are called in the order they are added.
+func (c *TestContext) AddStreamServerInterceptor(i grpc.StreamServerInterceptor) {
+	c.streamServerInterceptors = append(c.streamServerInterceptors, i)
+}
+
+// AddUnaryClientInterceptor adds an interceptor to a test client.
+// Interceptors are called in the order they are added.
+func (c *TestContext) AddUnaryClientInterceptor(i grpc.UnaryClientInterceptor) {
+	c.unaryClientInterceptors = append(c.unaryClientInterceptors, i)
+}
+
+// AddStreamClientInterceptor adds an interceptor to a test client.
+// Interceptors are called in the order they are added.
+func (c *TestContext) AddStreamClientInterceptor(i grpc.StreamClientInterceptor) {
+	c.streamClientInterceptors = append(c.streamClientInterceptors, i)
 }
 
 // Setup starts a server and creates a client connection.
+// Teardown is registered with testing.T.Cleanup.
 func (c *TestContext) Setup() {
 	if c.Service == nil {
 		c.t.Fatal("Should set errorstesting.TestService implementaiton")
 	}
+	c.t.Cleanup(c.Teardown)
 	c.setupServer()
 	c.setupClient()
 }
 
-// Teardown disconnects a client connection and stops a server
+// Teardown disconnects a client connection and stops a server.
+// It waits until the server stops, and it is safe to call more than once.
 func (c *TestContext) Teardown() {
-	time.Sleep(10 * time.Millisecond)
-	if c.serverListener != nil {
-		c.server.GracefulStop()
-		c.serverListener.Close()
-	}
-	if c.clientConn != nil {
-		c.clientConn.Close()
-	}
+	c.teardownOnce.Do(func() {
+		if c.clientConn != nil {
+			c.clientConn.Close()
+		}
+		if c.server != nil {
+			c.server.GracefulStop()
+			<-c.serverDone
+		}
+		if c.serverListener != nil {
+			c.serverListener.Close()
+		}
+	})
 }
 
 func (c *TestContext) setupServer() {
-	var err error
-	c.serverListener, err = net.Listen("tcp", "127.0.0.1:0")
-	if err != nil {
-		c.Teardown()
-		c.t.Fatal("Failed to listen local network")
+	if c.TCP {
+		var err error
+		c.serverListener, err = net.Listen("tcp", "127.0.0.1:0")
+		if err != nil {
+			c.Teardown()
+			c.t.Fatal("Failed to listen local network")
+		}
+	} else {
+		c.serverListener = bufconn.Listen(bufconnSize)
 	}
-	c.server = grpc.NewServer(c.ServerOpts...)
+	opts := append([]grpc.ServerOption{}, c.ServerOpts...)
+	if len(c.unaryServerInterceptors) > 0 {
+		opts = append(opts, grpc.UnaryInterceptor(chainUnaryServerInterceptors(c.unaryServerInterceptors)))
+	}
+	if len(c.streamServerInterceptors) > 0 {
+		opts = append(opts, grpc.StreamInterceptor(chainStreamServerInterceptors(c.streamServerInterceptors)))
+	}
+	c.server = grpc.NewServer(opts...)
 	RegisterTestServiceServer(c.server, c.Service)
-	go c.server.Serve(c.serverListener)
+	c.serverDone = make(chan struct{})
+	go func() {
+		defer close(c.serverDone)
+		c.server.Serve(c.serverListener)
+	}()
 }
 
 func (c *TestContext) setupClient() {
 	var err error
-	dialOpts := append(c.ClientOpts, grpc.WithBlock(), grpc.WithTimeout(2*time.Second), grpc.WithInsecure())
+	dialOpts := append([]grpc.DialOption{}, c.ClientOpts...)
+	if len(c.unaryClientInterceptors) > 0 {
+		dialOpts = append(dialOpts, grpc.WithUnaryInterceptor(chainUnaryClientInterceptors(c.unaryClientInterceptors)))
+	}
+	if len(c.streamClientInterceptors) > 0 {
+		dialOpts = append(dialOpts, grpc.WithStreamInterceptor(chainStreamClientInterceptors(c.streamClientInterceptors)))
+	}
+	if l, ok := c.serverListener.(*bufconn.Listener); ok {
+		dialOpts = append(dialOpts, grpc.WithDialer(func(string, time.Duration) (net.Conn, error) {
+			return l.Dial()
+		}))
+	}
+	dialOpts = append(dialOpts, grpc.WithBlock(), grpc.WithTimeout(2*time.Second), grpc.WithInsecure())
 	c.clientConn, err = grpc.Dial(c.serverListener.Addr().String(), dialOpts...)
 	if err != nil {
 		c.Teardown()
@@ -1,15 +1,6 @@
 // Code generated by protoc-gen-go. DO NOT EDIT.
 // source: empty.proto
 
-/*
-Package errorstesting is a generated protocol buffer package.
-
-It is generated from these files:
-	empty.proto
-
-It has these top-level messages:
-	Empty
-*/
 package errorstesting
 
 import proto "github.com/golang/protobuf/proto"
@@ -33,12 +24,34 @@ var _ = math.Inf
 const _ = proto.ProtoPackageIsVersion2 // please upgrade the proto package
 
 type Empty struct {
+	XXX_NoUnkeyedLiteral struct{} `json:"-"`
+	XXX_unrecognized     []byte   `json:"-"`
+	XXX_sizecache        int32    `json:"-"`
 }
 
-func (m *Empty) Reset()                    { *m = Empty{} }
-func (m *Empty) String() string            { return proto.CompactTextString(m) }
-func (*Empty) ProtoMessage()               {}
-func (*Empty) Descriptor() ([]byte, []int) { return fileDescriptor0, []int{0} }
+func (m *Empty) Reset()         { *m = Empty{} }
+func (m *Empty) String() string { return proto.CompactTextString(m) }
+func (*Empty) ProtoMessage()    {}
+func (*Empty) Descriptor() ([]byte, []int) {
+	return fileDescriptor_empty_8a7120916e0af638, []int{0}
+}
+func (m *Empty) XXX_Unmarshal(b []byte) error {
+	return xxx_messageInfo_Empty.Unmarshal(m, b)
+}
+func (m *Empty) XXX_Marshal(b []byte, deterministic bool) ([]byte, error) {
+	return xxx_messageInfo_Empty.Marshal(b, m, deterministic)
+}
+func (dst *Empty) XXX_Merge(src proto.Message) {
+	xxx_messageInfo_Empty.Merge(dst, src)
+}
+func (m *Empty) XXX_Size() int {
+	return xxx_messageInfo_Empty.Size(m)
+}
+func (m *Empty) XXX_DiscardUnknown() {
+	xxx_messageInfo_Empty.DiscardUnknown(m)
+}
+
+var xxx_messageInfo_Empty proto.InternalMessageInfo
 
 func init() {
 	proto.RegisterType((*Empty)(nil), "errorstesting.Empty")
@@ -56,6 +69,9 @@ const _ = grpc.SupportPackageIsVersion4
 
 type TestServiceClient interface {
 	EmptyCall(ctx context.Context, in *Empty, opts ...grpc.CallOption) (*Empty, error)
+	ServerStreamCall(ctx context.Context, in *Empty, opts ...grpc.CallOption) (TestService_ServerStreamCallClient, error)
+	ClientStreamCall(ctx context.Context, opts ...grpc.CallOption) (TestService_ClientStreamCallClient, error)
+	BidiStreamCall(ctx context.Context, opts ...grpc.CallOption) (TestService_BidiStreamCallClient, error)
 }
 
 type testServiceClient struct {
@@ -75,10 +91,110 @@ func (c *testServiceClient) EmptyCall(ctx context.Context, in *Empty, opts ...gr
 	return out, nil
 }
 
+func (c *testServiceClient) ServerStreamCall(ctx context.Context, in *Empty, opts ...grpc.CallOption) (TestService_ServerStreamCallClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_TestService_serviceDesc.Streams[0], c.cc, "/errorstesting.TestService/ServerStreamCall", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &testServiceServerStreamCallClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type TestService_ServerStreamCallClient interface {
+	Recv() (*Empty, error)
+	grpc.ClientStream
+}
+
+type testServiceServerStreamCallClient struct {
+	grpc.ClientStream
+}
+
+func (x *testServiceServerStreamCallClient) Recv() (*Empty, error) {
+	m := new(Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *testServiceClient) ClientStreamCall(ctx context.Context, opts ...grpc.CallOption) (TestService_ClientStreamCallClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_TestService_serviceDesc.Streams[1], c.cc, "/errorstesting.TestService/ClientStreamCall", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &testServiceClientStreamCallClient{stream}
+	return x, nil
+}
+
+type TestService_ClientStreamCallClient interface {
+	Send(*Empty) error
+	CloseAndRecv() (*Empty, error)
+	grpc.ClientStream
+}
+
+type testServiceClientStreamCallClient struct {
+	grpc.ClientStream
+}
+
+func (x *testServiceClientStreamCallClient) Send(m *Empty) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *testServiceClientStreamCallClient) CloseAndRecv() (*Empty, error) {
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	m := new(Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func (c *testServiceClient) BidiStreamCall(ctx context.Context, opts ...grpc.CallOption) (TestService_BidiStreamCallClient, error) {
+	stream, err := grpc.NewClientStream(ctx, &_TestService_serviceDesc.Streams[2], c.cc, "/errorstesting.TestService/BidiStreamCall", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &testServiceBidiStreamCallClient{stream}
+	return x, nil
+}
+
+type TestService_BidiStreamCallClient interface {
+	Send(*Empty) error
+	Recv() (*Empty, error)
+	grpc.ClientStream
+}
+
+type testServiceBidiStreamCallClient struct {
+	grpc.ClientStream
+}
+
+func (x *testServiceBidiStreamCallClient) Send(m *Empty) error {
+	return x.ClientStream.SendMsg(m)
+}
+
+func (x *testServiceBidiStreamCallClient) Recv() (*Empty, error) {
+	m := new(Empty)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // Server API for TestService service
 
 type TestServiceServer interface {
 	EmptyCall(context.Context, *Empty) (*Empty, error)
+	ServerStreamCall(*Empty, TestService_ServerStreamCallServer) error
+	ClientStreamCall(TestService_ClientStreamCallServer) error
+	BidiStreamCall(TestService_BidiStreamCallServer) error
 }
 
 func RegisterTestServiceServer(s *grpc.Server, srv TestServiceServer) {
@@ -103,6 +219,79 @@ func _TestService_EmptyCall_Handler(srv interface{}, ctx context.Context, dec fu
 	return interceptor(ctx, in, info, handler)
 }
 
+func _TestService_ServerStreamCall_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(Empty)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(TestServiceServer).ServerStreamCall(m, &testServiceServerStreamCallServer{stream})
+}
+
+type TestService_ServerStreamCallServer interface {
+	Send(*Empty) error
+	grpc.ServerStream
+}
+
+type testServiceServerStreamCallServer struct {
+	grpc.ServerStream
+}
+
+func (x *testServiceServerStreamCallServer) Send(m *Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func _TestService_ClientStreamCall_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TestServiceServer).ClientStreamCall(&testServiceClientStreamCallServer{stream})
+}
+
+type TestService_ClientStreamCallServer interface {
+	SendAndClose(*Empty) error
+	Recv() (*Empty, error)
+	grpc.ServerStream
+}
+
+type testServiceClientStreamCallServer struct {
+	grpc.ServerStream
+}
+
+func (x *testServiceClientStreamCallServer) SendAndClose(m *Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *testServiceClientStreamCallServer) Recv() (*Empty, error) {
+	m := new(Empty)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func _TestService_BidiStreamCall_Handler(srv interface{}, stream grpc.ServerStream) error {
+	return srv.(TestServiceServer).BidiStreamCall(&testServiceBidiStreamCallServer{stream})
+}
+
+type TestService_BidiStreamCallServer interface {
+	Send(*Empty) error
+	Recv() (*Empty, error)
+	grpc.ServerStream
+}
+
+type testServiceBidiStreamCallServer struct {
+	grpc.ServerStream
+}
+
+func (x *testServiceBidiStreamCallServer) Send(m *Empty) error {
+	return x.ServerStream.SendMsg(m)
+}
+
+func (x *testServiceBidiStreamCallServer) Recv() (*Empty, error) {
+	m := new(Empty)
+	if err := x.ServerStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 var _TestService_serviceDesc = grpc.ServiceDesc{
 	ServiceName: "errorstesting.TestService",
 	HandlerType: (*TestServiceServer)(nil),
@@ -112,19 +301,38 @@ var _TestService_serviceDesc = grpc.ServiceDesc{
 			Handler:    _TestService_EmptyCall_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "ServerStreamCall",
+			Handler:       _TestService_ServerStreamCall_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "ClientStreamCall",
+			Handler:       _TestService_ClientStreamCall_Handler,
+			ClientStreams: true,
+		},
+		{
+			StreamName:    "BidiStreamCall",
+			Handler:       _TestService_BidiStreamCall_Handler,
+			ServerStreams: true,
+			ClientStreams: true,
+		},
+	},
 	Metadata: "empty.proto",
 }
 
-func init() { proto.RegisterFile("empty.proto", fileDescriptor0) }
+func init() { proto.RegisterFile("empty.proto", fileDescriptor_empty_8a7120916e0af638) }
 
-var fileDescriptor0 = []byte{
-	// 100 bytes of a gzipped FileDescriptorProto
+var fileDescriptor_empty_8a7120916e0af638 = []byte{
+	// 140 bytes of a gzipped FileDescriptorProto
 	0x1f, 0x8b, 0x08, 0x00, 0x00, 0x00, 0x00, 0x00, 0x02, 0xff, 0xe2, 0xe2, 0x4e, 0xcd, 0x2d, 0x28,
 	0xa9, 0xd4, 0x2b, 0x28, 0xca, 0x2f, 0xc9, 0x17, 0xe2, 0x4d, 0x2d, 0x2a, 0xca, 0x2f, 0x2a, 0x2e,
-	0x49, 0x2d, 0x2e, 0xc9, 0xcc, 0x4b, 0x57, 0x62, 0xe7, 0x62, 0x75, 0x05, 0xc9, 0x1a, 0xb9, 0x71,
-	0x71, 0x87, 0xa4, 0x16, 0x97, 0x04, 0xa7, 0x16, 0x95, 0x65, 0x26, 0xa7, 0x0a, 0x99, 0x73, 0x71,
-	0x82, 0xc5, 0x9d, 0x13, 0x73, 0x72, 0x84, 0x44, 0xf4, 0x50, 0x34, 0xe9, 0x81, 0x65, 0xa4, 0xb0,
-	0x8a, 0x26, 0xb1, 0x81, 0xad, 0x31, 0x06, 0x04, 0x00, 0x00, 0xff, 0xff, 0x81, 0xc3, 0x97, 0x87,
-	0x75, 0x00, 0x00, 0x00,
+	0x49, 0x2d, 0x2e, 0xc9, 0xcc, 0x4b, 0x57, 0x62, 0xe7, 0x62, 0x75, 0x05, 0xc9, 0x1a, 0xf5, 0x30,
+	0x71, 0x71, 0x87, 0xa4, 0x16, 0x97, 0x04, 0xa7, 0x16, 0x95, 0x65, 0x26, 0xa7, 0x0a, 0x99, 0x73,
+	0x71, 0x82, 0x25, 0x9c, 0x13, 0x73, 0x72, 0x84, 0x44, 0xf4, 0x50, 0x74, 0xe9, 0x81, 0x65, 0xa4,
+	0xb0, 0x8a, 0x0a, 0x39, 0x70, 0x09, 0x80, 0xcc, 0x48, 0x2d, 0x0a, 0x2e, 0x29, 0x4a, 0x4d, 0xcc,
+	0x25, 0x55, 0xbf, 0x01, 0x23, 0xc8, 0x04, 0xe7, 0x9c, 0xcc, 0xd4, 0xbc, 0x12, 0xf2, 0x4c, 0xd0,
+	0x00, 0x99, 0xc0, 0xe7, 0x94, 0x99, 0x92, 0x49, 0xae, 0x7e, 0x03, 0xc6, 0x24, 0x36, 0x70, 0x68,
+	0x19, 0x03, 0x06, 0x00, 0xa8, 0x77, 0xd6, 0x29, 0x3c, 0x01, 0x00, 0x00,
 }
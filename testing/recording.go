@@ -0,0 +1,145 @@
+package errorstesting
+
+import (
+	"sort"
+	"sync"
+	"sync/atomic"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+var callSeq int64
+
+// Call is a call of a RecordingHandler.
+type Call struct {
+	// Seq is a sequence number of the call, shared among all RecordingHandlers.
+	Seq int64
+	// Handler is a name of the handler.
+	Handler string
+
+	Context context.Context
+	// Request is a request of a unary server, or the last received message of a stream server.
+	Request interface{}
+	// Response is the last sent message of a stream server. It is nil on unary servers.
+	Response   interface{}
+	UnaryInfo  *grpc.UnaryServerInfo
+	StreamInfo *grpc.StreamServerInfo
+	FullMethod string
+
+	// Err is an error passed to the handler.
+	Err error
+	// ReturnedErr is an error returned from the handler.
+	ReturnedErr error
+}
+
+// RecordingHandler is an error handler for unary and stream servers that records its calls.
+// It is safe for concurrent use.
+type RecordingHandler struct {
+	// Name is used for identifying the handler in Order.
+	Name string
+	// Func returns an error passed to the next handler. The handler returns a given error as it is when Func is nil.
+	Func func(context.Context, error) error
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// NewRecordingHandler returns a new RecordingHandler with the name.
+func NewRecordingHandler(name string) *RecordingHandler {
+	return &RecordingHandler{Name: name}
+}
+
+// HandleUnaryServerError implements the UnaryServerErrorHandler interface.
+func (h *RecordingHandler) HandleUnaryServerError(c context.Context, req interface{}, info *grpc.UnaryServerInfo, err error) error {
+	call := Call{Context: c, Request: req, UnaryInfo: info, Err: err}
+	if info != nil {
+		call.FullMethod = info.FullMethod
+	}
+	return h.record(call)
+}
+
+// HandleStreamServerError implements the StreamServerErrorHandler interface.
+func (h *RecordingHandler) HandleStreamServerError(c context.Context, req interface{}, resp interface{}, info *grpc.StreamServerInfo, err error) error {
+	call := Call{Context: c, Request: req, Response: resp, StreamInfo: info, Err: err}
+	if info != nil {
+		call.FullMethod = info.FullMethod
+	}
+	return h.record(call)
+}
+
+func (h *RecordingHandler) record(call Call) error {
+	call.Seq = atomic.AddInt64(&callSeq, 1)
+	call.Handler = h.Name
+	call.ReturnedErr = call.Err
+	if h.Func != nil {
+		call.ReturnedErr = h.Func(call.Context, call.Err)
+	}
+	h.mu.Lock()
+	h.calls = append(h.calls, call)
+	h.mu.Unlock()
+	return call.ReturnedErr
+}
+
+// Calls returns recorded calls in the order of occurrence.
+func (h *RecordingHandler) Calls() []Call {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return append([]Call(nil), h.calls...)
+}
+
+// Count returns the number of recorded calls.
+func (h *RecordingHandler) Count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return len(h.calls)
+}
+
+// CountMethod returns the number of recorded calls for the full method.
+func (h *RecordingHandler) CountMethod(fullMethod string) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	n := 0
+	for _, call := range h.calls {
+		if call.FullMethod == fullMethod {
+			n++
+		}
+	}
+	return n
+}
+
+// Called returns true if the handler has been called.
+func (h *RecordingHandler) Called() bool {
+	return h.Count() > 0
+}
+
+// LastCall returns the last recorded call.
+func (h *RecordingHandler) LastCall() (Call, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if len(h.calls) == 0 {
+		return Call{}, false
+	}
+	return h.calls[len(h.calls)-1], true
+}
+
+// Reset clears recorded calls.
+func (h *RecordingHandler) Reset() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.calls = nil
+}
+
+// Order returns names of handlers in the order they were called.
+func Order(handlers ...*RecordingHandler) []string {
+	var calls []Call
+	for _, h := range handlers {
+		calls = append(calls, h.Calls()...)
+	}
+	sort.Slice(calls, func(i, j int) bool { return calls[i].Seq < calls[j].Seq })
+	names := make([]string, len(calls))
+	for i, call := range calls {
+		names[i] = call.Handler
+	}
+	return names
+}
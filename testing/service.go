@@ -0,0 +1,31 @@
+package errorstesting
+
+import (
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UnimplementedTestServiceServer can be embedded in TestServiceServer implementations.
+// Its methods return codes.Unimplemented.
+type UnimplementedTestServiceServer struct{}
+
+// EmptyCall implements TestServiceServer.
+func (UnimplementedTestServiceServer) EmptyCall(context.Context, *Empty) (*Empty, error) {
+	return nil, status.Error(codes.Unimplemented, "method EmptyCall not implemented")
+}
+
+// ServerStreamCall implements TestServiceServer.
+func (UnimplementedTestServiceServer) ServerStreamCall(*Empty, TestService_ServerStreamCallServer) error {
+	return status.Error(codes.Unimplemented, "method ServerStreamCall not implemented")
+}
+
+// ClientStreamCall implements TestServiceServer.
+func (UnimplementedTestServiceServer) ClientStreamCall(TestService_ClientStreamCallServer) error {
+	return status.Error(codes.Unimplemented, "method ClientStreamCall not implemented")
+}
+
+// BidiStreamCall implements TestServiceServer.
+func (UnimplementedTestServiceServer) BidiStreamCall(TestService_BidiStreamCallServer) error {
+	return status.Error(codes.Unimplemented, "method BidiStreamCall not implemented")
+}
@@ -1,6 +1,13 @@
 package grpcerrors
 
 import (
+	"crypto/rand"
+	"encoding/binary"
+	"encoding/hex"
+	"sync"
+	"sync/atomic"
+	"time"
+
 	"golang.org/x/net/context"
 	"google.golang.org/grpc"
 )
@@ -59,3 +66,146 @@ func (ch *composedStreamServerErrorHandler) HandleStreamServerError(
 	}
 	return err
 }
+
+type composedUnaryClientErrorHandler struct {
+	handlers []UnaryClientErrorHandler
+}
+
+func composeUnaryClientErrorHandlers(handlers []UnaryClientErrorHandler) UnaryClientErrorHandler {
+	return &composedUnaryClientErrorHandler{
+		handlers: handlers,
+	}
+}
+
+func (ch *composedUnaryClientErrorHandler) HandleUnaryClientError(
+	c context.Context,
+	method string,
+	req interface{},
+	reply interface{},
+	opts []grpc.CallOption,
+	err error,
+) error {
+	if err != nil {
+		for _, h := range ch.handlers {
+			err = h.HandleUnaryClientError(c, method, req, reply, opts, err)
+			if err == nil {
+				break
+			}
+		}
+	}
+	return err
+}
+
+type composedStreamClientErrorHandler struct {
+	handlers []StreamClientErrorHandler
+}
+
+func composeStreamClientErrorHandlers(handlers []StreamClientErrorHandler) StreamClientErrorHandler {
+	return &composedStreamClientErrorHandler{
+		handlers: handlers,
+	}
+}
+
+func (ch *composedStreamClientErrorHandler) HandleStreamClientError(
+	c context.Context,
+	req interface{},
+	resp interface{},
+	desc *grpc.StreamDesc,
+	method string,
+	opts []grpc.CallOption,
+	err error,
+) error {
+	if err != nil {
+		for _, h := range ch.handlers {
+			err = h.HandleStreamClientError(c, req, resp, desc, method, opts, err)
+			if err == nil {
+				break
+			}
+		}
+	}
+	return err
+}
+
+// randRead is replaced in tests.
+var randRead = rand.Read
+
+// fallbackIDCount makes IDs unique when random IDs are not available.
+var fallbackIDCount uint64
+
+// newRandomID returns a random hex ID. It falls back to an ID based on the current time when the random source fails.
+func newRandomID() string {
+	b := make([]byte, 16)
+	if _, err := randRead(b); err != nil {
+		binary.BigEndian.PutUint64(b[:8], uint64(time.Now().UnixNano()))
+		binary.BigEndian.PutUint64(b[8:], atomic.AddUint64(&fallbackIDCount, 1))
+	}
+	return hex.EncodeToString(b)
+}
+
+// handlerWrapper is implemented by error handlers that delegate errors to other error handlers.
+type handlerWrapper interface {
+	// wrappedHandlers returns error handlers that are called for the full method.
+	wrappedHandlers(fullMethod string) []interface{}
+}
+
+// findHandler returns the first error handler matched with f from handlers and handlers wrapped by them for the full method.
+// It returns nil when no handlers are matched.
+func findHandler(handlers []interface{}, fullMethod string, f func(interface{}) bool) interface{} {
+	for _, h := range handlers {
+		if f(h) {
+			return h
+		}
+		if w, ok := h.(handlerWrapper); ok {
+			if found := findHandler(w.wrappedHandlers(fullMethod), fullMethod, f); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
+
+// methodFeatures are features that error handlers enable for a full method.
+type methodFeatures struct {
+	recovery       bool
+	faults         *FaultInjector
+	streamRecorder *streamRecorderHandler
+}
+
+// methodFeaturesCache caches methodFeatures by full methods,
+// so that handler trees are not walked on every call even though handlers such as ForMethods enable features by methods.
+type methodFeaturesCache struct {
+	handlers []interface{}
+	features sync.Map
+}
+
+func newMethodFeaturesCache(handlers []interface{}) *methodFeaturesCache {
+	return &methodFeaturesCache{handlers: handlers}
+}
+
+func (c *methodFeaturesCache) get(fullMethod string) *methodFeatures {
+	if f, ok := c.features.Load(fullMethod); ok {
+		return f.(*methodFeatures)
+	}
+	f, _ := c.features.LoadOrStore(fullMethod, &methodFeatures{
+		recovery:       hasPanicRecovery(c.handlers, fullMethod),
+		faults:         findFaultInjector(c.handlers, fullMethod),
+		streamRecorder: findStreamRecorder(c.handlers, fullMethod),
+	})
+	return f.(*methodFeatures)
+}
+
+func unaryServerHandlers(handlers []UnaryServerErrorHandler) []interface{} {
+	hs := make([]interface{}, len(handlers))
+	for i, h := range handlers {
+		hs[i] = h
+	}
+	return hs
+}
+
+func streamServerHandlers(handlers []StreamServerErrorHandler) []interface{} {
+	hs := make([]interface{}, len(handlers))
+	for i, h := range handlers {
+		hs[i] = h
+	}
+	return hs
+}